@@ -19,21 +19,17 @@ var numAcceleratorTypes int
 var unitsAvail []int               // [numAcceleratorTypes]
 var acceleratorTypesMatrix [][]int // [numAcceleratorTypes][numAccelerators]
 
+var solverTimeout int // seconds, default if zero
+
 // create problem instance
 func CreateProblem(problemType config.ProblemType, isLimited bool) (core.Problem, error) {
-	var p core.Problem
-	var err error
 	// create a new problem instance
-	switch problemType {
-	case config.SINGLE, config.MULTI:
-		p, err = core.CreateCplexProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
-			ratePerReplica, arrivalRates)
-	default:
-		return nil, fmt.Errorf("unknown problem type: %s", problemType)
-	}
+	p, err := core.CreateCplexProblem(problemType, numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+		ratePerReplica, arrivalRates)
 	if err != nil {
 		return nil, err
 	}
+	p.SetSolverTimeout(solverTimeout)
 
 	// set accelerator count limited option
 	if isLimited {
@@ -45,6 +41,12 @@ func CreateProblem(problemType config.ProblemType, isLimited bool) (core.Problem
 			SetFileNames(p, "single-limited")
 		case config.MULTI:
 			SetFileNames(p, "multi-limited")
+		case config.THROUGHPUT:
+			SetFileNames(p, "throughput-limited")
+		case config.FAIRNESS:
+			SetFileNames(p, "fairness-limited")
+		case config.HYBRID:
+			SetFileNames(p, "hybrid-limited")
 		}
 	} else {
 		p.UnSetLimited()
@@ -53,6 +55,12 @@ func CreateProblem(problemType config.ProblemType, isLimited bool) (core.Problem
 			SetFileNames(p, "single-unlimited")
 		case config.MULTI:
 			SetFileNames(p, "multi-unlimited")
+		case config.THROUGHPUT:
+			SetFileNames(p, "throughput-unlimited")
+		case config.FAIRNESS:
+			SetFileNames(p, "fairness-unlimited")
+		case config.HYBRID:
+			SetFileNames(p, "hybrid-unlimited")
 		}
 	}
 	return p, nil
@@ -63,6 +71,9 @@ func PrintResults(p core.Problem) {
 	fmt.Printf("Solution type: %v\n", p.GetSolutionType())
 	fmt.Printf("Solution time: %d msec\n", p.GetSolutionTimeMsec())
 	fmt.Printf("Objective value: %v\n", p.GetObjectiveValue())
+	if p.GetSolutionType() != core.OPTIMAL {
+		fmt.Printf("Best bound: %v (gap %.2f%%)\n", p.GetBestBound(), 100*p.GetMIPGap())
+	}
 
 	numReplicas := p.GetNumReplicas()
 	fmt.Println(utils.Pretty2D("numReplicas", numReplicas))
@@ -75,11 +86,15 @@ func PrintResults(p core.Problem) {
 		unitsUsed := p.GetUnitsUsed()
 		fmt.Println(utils.Pretty1D("unitsUsed", unitsUsed))
 	}
+
+	if pc, ok := p.(*core.CplexProblem); ok && pc.GetLastRunDir() != "" {
+		fmt.Printf("Files: %s\n", pc.GetLastRunDir())
+	}
 }
 
-func SetFileNames(p core.Problem, name string) {
-	pc := p.(*core.CplexProblem)
-	pc.SetModelFileName(name + ".mod")
-	pc.SetDataFileName(name + ".dat")
-	pc.SetOutputFileName(name + ".txt")
+// set names of generated model and output files, which are kept after solving
+func SetFileNames(p *core.CplexProblem, name string) {
+	p.SetModelFileName(name + ".mod")
+	p.SetOutputFileName(name + ".txt")
+	p.SetKeepFiles(true)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/llm-inferno/lpsolve/pkg/config"
 )
@@ -14,6 +15,15 @@ func main() {
 		problemType = config.GetProblemType(os.Args[1])
 	}
 
+	// get solver timeout argument (seconds), or use default
+	if len(os.Args) > 2 {
+		var err error
+		if solverTimeout, err = strconv.Atoi(os.Args[2]); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
 	numServers = 5
 	numAccelerators = 8
 	numAcceleratorTypes = 8
@@ -58,7 +68,10 @@ func main() {
 	// unlimited case
 	fmt.Println("Solution of Unlimited case:")
 	fmt.Println("---------------------------")
-	if p, err := CreateProblem(problemType, false); err != nil || p.Solve() != nil {
+	if p, err := CreateProblem(problemType, false); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := p.Solve(); err != nil {
 		fmt.Println(err)
 		return
 	} else {
@@ -69,7 +82,10 @@ func main() {
 	//limited case
 	fmt.Println("Solution of Limited case:")
 	fmt.Println("-------------------------")
-	if p, err := CreateProblem(problemType, true); err != nil || p.Solve() != nil {
+	if p, err := CreateProblem(problemType, true); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := p.Solve(); err != nil {
 		fmt.Println(err)
 		return
 	} else {
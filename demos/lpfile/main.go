@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/core"
+	"github.com/llm-inferno/lpsolve/pkg/lpformat"
+)
+
+// solve a model in an lp_solve LP file, e.g. go run ./demos/lpfile demos/cmd/test-mip.lp go
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("usage: lpfile <file.lp> [backend]")
+		return
+	}
+
+	m, err := lpformat.ParseFile(os.Args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// get solver backend argument, or use default
+	backend := core.DefaultBackend()
+	if len(os.Args) > 2 {
+		if backend, err = core.NewBackend(os.Args[2]); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	fmt.Printf("Backend: %s\n", backend.Name())
+	fmt.Printf("Model: %d variables, %d constraints\n", m.NumVars(), m.NumConstraints())
+
+	timeout := time.Duration(config.DefaultSolverTimeout) * time.Second
+	sol, err := backend.Solve(context.Background(), m, timeout)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Solution type: %v\n", sol.Status)
+	if sol.Status != core.OPTIMAL && sol.Status != core.SUBOPTIMAL {
+		return
+	}
+	fmt.Printf("Objective value: %v\n", sol.Objective)
+	fmt.Printf("Variable values:\n")
+	for k, v := range sol.Values {
+		fmt.Printf("%s = %v\n", m.VarName(k), v)
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/llm-inferno/lpsolve/pkg/config"
 	"github.com/llm-inferno/lpsolve/pkg/core"
@@ -19,6 +21,9 @@ var numAcceleratorTypes int
 var unitsAvail []int               // [numAcceleratorTypes]
 var acceleratorTypesMatrix [][]int // [numAcceleratorTypes][numAccelerators]
 
+var backend core.Backend // solver backend, default if nil
+var exportPrefix string  // prefix of LP and MPS files to export models to, none if empty
+
 // create problem instance
 func CreateProblem(problemType config.ProblemType, isLimited bool) (core.Problem, error) {
 	var p core.Problem
@@ -31,12 +36,22 @@ func CreateProblem(problemType config.ProblemType, isLimited bool) (core.Problem
 	case config.MULTI:
 		p, err = core.CreateMultiAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
 			ratePerReplica, arrivalRates)
+	case config.THROUGHPUT:
+		p, err = core.CreateThroughputProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	case config.FAIRNESS:
+		p, err = core.CreateFairnessProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	case config.HYBRID:
+		p, err = core.CreateHybridAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
 	default:
 		return nil, fmt.Errorf("unknown problem type: %s", problemType)
 	}
 	if err != nil {
 		return nil, err
 	}
+	p.SetBackend(backend)
 
 	// set accelerator count limited option
 	if isLimited {
@@ -50,11 +65,40 @@ func CreateProblem(problemType config.ProblemType, isLimited bool) (core.Problem
 	return p, nil
 }
 
+// export model of problem to <exportPrefix>-<name>.lp and .mps files
+func ExportModel(p core.Problem, name string) error {
+	if exportPrefix == "" {
+		return nil
+	}
+	for _, f := range []struct {
+		ext   string
+		write func(w io.Writer) error
+	}{{"lp", p.WriteLP}, {"mps", p.WriteMPS}} {
+		fileName := fmt.Sprintf("%s-%s.%s", exportPrefix, name, f.ext)
+		file, err := os.Create(fileName)
+		if err != nil {
+			return err
+		}
+		if err := f.write(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Model written to %s\n", fileName)
+	}
+	return nil
+}
+
 // print solution details
 func PrintResults(p core.Problem) {
 	fmt.Printf("Solution type: %v\n", p.GetSolutionType())
 	fmt.Printf("Solution time: %d msec\n", p.GetSolutionTimeMsec())
 	fmt.Printf("Objective value: %v\n", p.GetObjectiveValue())
+	if p.GetSolutionType() != core.OPTIMAL {
+		fmt.Printf("Best bound: %v (gap %.2f%%)\n", p.GetBestBound(), 100*p.GetMIPGap())
+	}
 
 	numReplicas := p.GetNumReplicas()
 	fmt.Println(utils.Pretty2D("numReplicas", numReplicas))
@@ -62,9 +106,29 @@ func PrintResults(p core.Problem) {
 	instancesUsed := p.GetInstancesUsed()
 	fmt.Println(utils.Pretty1D("instancesUsed", instancesUsed))
 
+	utilization := p.GetUtilization()
+	fmt.Println(utils.Pretty1D("utilization", utilization))
+
 	if p.IsLimited() {
 		fmt.Println(utils.Pretty1D("unitsAvail", unitsAvail))
 		unitsUsed := p.GetUnitsUsed()
 		fmt.Println(utils.Pretty1D("unitsUsed", unitsUsed))
 	}
+
+	switch q := p.(type) {
+	case *core.MultiAssignProblem:
+		if !q.IsSoftDemand() {
+			break
+		}
+		fmt.Println(utils.Pretty1D("servedRates", p.GetServedRates()))
+		fmt.Println(utils.Pretty1D("droppedRates", p.GetDroppedRates()))
+	case *core.ThroughputProblem:
+		fmt.Printf("Weighted served rate: %v\n", q.GetWeightedServedRate())
+		fmt.Println(utils.Pretty1D("servedRates", p.GetServedRates()))
+		fmt.Println(utils.Pretty1D("droppedRates", p.GetDroppedRates()))
+	case *core.FairnessProblem:
+		fmt.Printf("Fair share: %v\n", q.GetFairShare())
+		fmt.Println(utils.Pretty1D("servedRates", p.GetServedRates()))
+		fmt.Println(utils.Pretty1D("droppedRates", p.GetDroppedRates()))
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/core"
 )
 
 func main() {
@@ -14,6 +15,20 @@ func main() {
 		problemType = config.GetProblemType(os.Args[1])
 	}
 
+	// get solver backend argument, or use default
+	if len(os.Args) > 2 {
+		var err error
+		if backend, err = core.NewBackend(os.Args[2]); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	// get prefix of files to export models to, if any
+	if len(os.Args) > 3 {
+		exportPrefix = os.Args[3]
+	}
+
 	numServers = 5
 	numAccelerators = 8
 	numAcceleratorTypes = 8
@@ -53,12 +68,21 @@ func main() {
 	arrivalRates = []float64{10, 20, 30, 40, 50}
 
 	fmt.Printf("Problem type: %v\n", problemType)
+	if backend != nil {
+		fmt.Printf("Backend: %s\n", backend.Name())
+	}
 	fmt.Println()
 
 	// unlimited case
 	fmt.Println("Solution of Unlimited case:")
 	fmt.Println("---------------------------")
-	if p, err := CreateProblem(problemType, false); err != nil || p.Solve() != nil {
+	if p, err := CreateProblem(problemType, false); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := ExportModel(p, "unlimited"); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := p.Solve(); err != nil {
 		fmt.Println(err)
 		return
 	} else {
@@ -69,7 +93,52 @@ func main() {
 	//limited case
 	fmt.Println("Solution of Limited case:")
 	fmt.Println("-------------------------")
-	if p, err := CreateProblem(problemType, true); err != nil || p.Solve() != nil {
+	if p, err := CreateProblem(problemType, true); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := ExportModel(p, "limited"); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := p.Solve(); err != nil {
+		fmt.Println(err)
+		return
+	} else {
+		PrintResults(p)
+	}
+	fmt.Println()
+
+	// scarce case, with fewer accelerator units than needed to serve all arrival rates:
+	// soft demand with a penalty on unserved rate, maximum throughput weighted by priority, or max-min fairness
+	var setScarceOption func(p core.Problem) error
+	switch problemType {
+	case config.MULTI:
+		setScarceOption = func(p core.Problem) error {
+			return p.(*core.MultiAssignProblem).SetSoftDemand([]float64{10, 10, 10, 10, 10})
+		}
+	case config.THROUGHPUT:
+		setScarceOption = func(p core.Problem) error {
+			return p.(*core.ThroughputProblem).SetPriorities([]float64{5, 4, 3, 2, 1})
+		}
+	case config.FAIRNESS:
+		setScarceOption = func(p core.Problem) error {
+			return p.(*core.FairnessProblem).SetWeights([]float64{1, 1, 1, 2, 2})
+		}
+	default:
+		return
+	}
+	fmt.Println("Solution of Scarce case:")
+	fmt.Println("------------------------")
+	unitsAvail = []int{5, 6, 2, 1, 1, 1, 1, 32}
+	if p, err := CreateProblem(problemType, true); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := setScarceOption(p); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := ExportModel(p, "scarce"); err != nil {
+		fmt.Println(err)
+		return
+	} else if err := p.Solve(); err != nil {
 		fmt.Println(err)
 		return
 	} else {
@@ -1,4 +1,12 @@
 package config
 
+import "os"
+
 // default timeout for LP solver in seconds
 var DefaultSolverTimeout = 60
+
+// default solver backend (lp_solve, cplex, or go), the build default if empty
+var DefaultBackend = os.Getenv("SOLVER_BACKEND")
+
+// default most number of accelerator kinds assigned to a server in HYBRID problems
+var DefaultMaxKinds = 2
@@ -4,13 +4,16 @@ package config
 type ProblemType int
 
 const (
-	SINGLE ProblemType = iota // a single kind of accelerator to a server
-	MULTI                     // multiple kinds of accelerators to a server
+	SINGLE     ProblemType = iota // a single kind of accelerator to a server
+	MULTI                         // multiple kinds of accelerators to a server
+	THROUGHPUT                    // maximum weighted served rate under limited capacity
+	FAIRNESS                      // maximum least served fraction of servers under limited capacity
+	HYBRID                        // at most a number of kinds of accelerators to a server
 	UNKNOWN
 )
 
 func (pt ProblemType) String() string {
-	return [...]string{"SINGLE", "MULTI", "UNKNOWN"}[pt]
+	return [...]string{"SINGLE", "MULTI", "THROUGHPUT", "FAIRNESS", "HYBRID", "UNKNOWN"}[pt]
 }
 
 func GetProblemType(s string) ProblemType {
@@ -19,6 +22,12 @@ func GetProblemType(s string) ProblemType {
 		return SINGLE
 	case "MULTI":
 		return MULTI
+	case "THROUGHPUT":
+		return THROUGHPUT
+	case "FAIRNESS":
+		return FAIRNESS
+	case "HYBRID":
+		return HYBRID
 	default:
 		return UNKNOWN
 	}
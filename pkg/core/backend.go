@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// extra time given to a solver beyond its own time limit, to report its incumbent, before it is aborted
+const solverTimeoutGrace = 2 * time.Second
+
+// solver of a Model, e.g. lp_solve or CPLEX
+type Backend interface {
+	// name of the solver
+	Name() string
+	// solve model within the timeout, aborting the solver when the context is done
+	Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error)
+}
+
+// constructors of available backends, by name of solver;
+// lp_solve is only available when built with cgo and without the purego tag
+var backendConstructors = map[string]func() Backend{
+	"cplex": func() Backend { return NewCplexBackend() },
+	"go":    func() Backend { return NewGoBackend() },
+}
+
+// create a backend given the name of its solver
+func NewBackend(name string) (Backend, error) {
+	if newBackend, ok := backendConstructors[name]; ok {
+		return newBackend(), nil
+	}
+	return nil, fmt.Errorf("unknown backend: %s", name)
+}
+
+// create the default backend: the configured one if any, otherwise lp_solve if available, otherwise pure Go
+func DefaultBackend() Backend {
+	for _, name := range []string{config.DefaultBackend, "lp_solve"} {
+		if backend, err := NewBackend(name); err == nil {
+			return backend
+		}
+	}
+	return NewGoBackend()
+}
+
+// result of solving a Model
+type Solution struct {
+	Status       SolutionType
+	SolverStatus string    // solver specific status, if any
+	CplexStatus  int       // CPLEX status code, zero if not solved by CPLEX
+	Objective    float64   // value of objective function
+	Values       []float64 // values of variables [len(model.Model.Vars)]
+	BestBound    float64   // best bound on the objective value
+}
+
+// solution status, values as reported by lp_solve
+type SolutionType int
+
+const (
+	NOMEMORY    SolutionType = -2
+	OPTIMAL     SolutionType = 0
+	SUBOPTIMAL  SolutionType = 1
+	INFEASIBLE  SolutionType = 2
+	UNBOUNDED   SolutionType = 3
+	DEGENERATE  SolutionType = 4
+	NUMFAILURE  SolutionType = 5
+	USERABORT   SolutionType = 6
+	TIMEOUT     SolutionType = 7
+	PROCFAIL    SolutionType = 10
+	PROCBREAK   SolutionType = 11
+	FEASFOUND   SolutionType = 12
+	NOFEASFOUND SolutionType = 13
+)
+
+func (t SolutionType) String() string {
+	switch t {
+	case NOMEMORY:
+		return "NOMEMORY"
+	case OPTIMAL:
+		return "OPTIMAL"
+	case SUBOPTIMAL:
+		return "SUBOPTIMAL"
+	case INFEASIBLE:
+		return "INFEASIBLE"
+	case UNBOUNDED:
+		return "UNBOUNDED"
+	case DEGENERATE:
+		return "DEGENERATE"
+	case NUMFAILURE:
+		return "NUMFAILURE"
+	case USERABORT:
+		return "USERABORT"
+	case TIMEOUT:
+		return "TIMEOUT"
+	case PROCFAIL:
+		return "PROCFAIL"
+	case PROCBREAK:
+		return "PROCBREAK"
+	case FEASFOUND:
+		return "FEASFOUND"
+	case NOFEASFOUND:
+		return "NOFEASFOUND"
+	default:
+		return fmt.Sprintf("SolutionType(%d)", int(t))
+	}
+}
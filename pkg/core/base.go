@@ -2,12 +2,13 @@ package core
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"io"
+	"math"
 	"time"
 
-	"github.com/draffensperger/golp"
 	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/model"
 )
 
 // Base optimization problem
@@ -20,32 +21,49 @@ type BaseProblem struct {
 	arrivalRates           []float64   // arrival rates to servers [numServers]
 	isLimited              bool        // solution limited to the available number of accelerator types
 
-	solutionType     golp.SolutionType
+	minReplicas     []int   // least number of replicas of servers [numServers], none if nil
+	maxReplicas     []int   // most number of replicas of servers [numServers], none if nil or negative
+	maxPairReplicas [][]int // most number of replicas of server and accelerator pairs [numServers][numAccelerators], none if nil or negative
+
+	targetUtilization []float64 // target utilization of replicas of servers [numServers], one if nil
+
+	solutionType     SolutionType
 	solutionTimeMsec int64
-	objectiveValue   float64 // value of objective function
-	numReplicas      [][]int // resulting number of replicas [numServers][numAccelerators]
-	instancesUsed    []int   // number of used accelerator instances [numAccelerators]
+	objectiveValue   float64   // value of objective function
+	bestBound        float64   // best bound on the objective value
+	mipGap           float64   // relative gap between objective value and best bound
+	numReplicas      [][]int   // resulting number of replicas [numServers][numAccelerators]
+	instancesUsed    []int     // number of used accelerator instances [numAccelerators]
+	servedRates      []float64 // arrival rate served by the replicas [numServers]
+	droppedRates     []float64 // arrival rate not served [numServers]
+	utilization      []float64 // served rate relative to the max rate of the replicas [numServers]
 
 	numAcceleratorTypes    int
 	acceleratorTypesMatrix [][]int // [numAcceleratorTypes][numAccelerators]: number of unit types for an accelerator
 	unitsAvail             []int   // available number of accelerator units [numAcceleratorTypes]
 	unitsUsed              []int   // number of used units of accelerator [numAcceleratorTypes]
 
-	lp               *golp.LP // lp_solve problem model
-	solverTimeoutSec int      // override default timeout
+	model            *model.Model  // solver-neutral problem model
+	x                [][]model.Var // model variables [numServers][numAccelerators], noVar if excluded
+	y                [][]model.Var // selection variables [numServers][numAccelerators], if x are linked replicas, noVar if excluded
+	demandRows       []int         // model rows of the demand constraints [numServers]
+	capRows          []int         // model rows of the capacity constraints [numAcceleratorTypes], if limited
+	solution         *Solution     // solution of model
+	backend          Backend       // solver of model
+	solverTimeoutSec int           // override default timeout
 
-	Setup func() error // pre-solve setup
-	Solve func() error // solve problem
+	Setup        func() error                    // pre-solve setup
+	Screen       func() *Screening               // pre-solve feasibility screening
+	Solve        func() error                    // solve problem
+	SolveContext func(ctx context.Context) error // solve problem, aborting when the context is done
 }
 
 // create an instance of base problem
 func CreateBaseProblem(numServers int, numAccelerators int, instanceCost []float64, numInstancesPerReplica [][]int,
 	ratePerReplica [][]float64, arrivalRates []float64) (*BaseProblem, error) {
-	if numServers <= 0 || numAccelerators <= 0 || len(instanceCost) != numAccelerators ||
-		len(numInstancesPerReplica) != numServers || len(numInstancesPerReplica[0]) != numAccelerators ||
-		len(ratePerReplica) != numServers || len(ratePerReplica[0]) != numAccelerators ||
-		len(arrivalRates) != numServers {
-		return nil, errors.New("inconsistent problem size")
+	if err := validateProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+		ratePerReplica, arrivalRates); err != nil {
+		return nil, err
 	}
 	return &BaseProblem{
 		numServers:             numServers,
@@ -55,14 +73,14 @@ func CreateBaseProblem(numServers int, numAccelerators int, instanceCost []float
 		ratePerReplica:         ratePerReplica,
 		arrivalRates:           arrivalRates,
 		isLimited:              false,
+		backend:                DefaultBackend(),
 	}, nil
 }
 
 // set limited accelerator units option
 func (p *BaseProblem) SetLimited(numAcceleratorTypes int, unitsAvail []int, acceleratorTypesMatrix [][]int) error {
-	if len(unitsAvail) != numAcceleratorTypes || len(acceleratorTypesMatrix) != numAcceleratorTypes ||
-		len(acceleratorTypesMatrix[0]) != p.numAccelerators {
-		return errors.New("inconsistent dimension")
+	if err := validateLimited(p.numAccelerators, numAcceleratorTypes, unitsAvail, acceleratorTypesMatrix); err != nil {
+		return err
 	}
 	p.isLimited = true
 	p.numAcceleratorTypes = numAcceleratorTypes
@@ -80,6 +98,78 @@ func (p *BaseProblem) IsLimited() bool {
 	return p.isLimited
 }
 
+// set bounds on the number of replicas: least and most replicas of servers [numServers],
+// and most replicas of server and accelerator pairs [numServers][numAccelerators];
+// any may be nil for no bounds, and a negative most number of replicas is no bound
+func (p *BaseProblem) SetReplicaBounds(minReplicas []int, maxReplicas []int, maxPairReplicas [][]int) error {
+	if err := validateReplicaBounds(p.numServers, p.numAccelerators, minReplicas, maxReplicas, maxPairReplicas); err != nil {
+		return err
+	}
+	p.minReplicas = minReplicas
+	p.maxReplicas = maxReplicas
+	p.maxPairReplicas = maxPairReplicas
+	return nil
+}
+
+// unset bounds on the number of replicas
+func (p *BaseProblem) UnSetReplicaBounds() {
+	p.minReplicas = nil
+	p.maxReplicas = nil
+	p.maxPairReplicas = nil
+}
+
+// set target utilization of replicas of servers [numServers], in (0, 1]:
+// replicas serve at most the target utilization times their rate, leaving headroom for traffic spikes;
+// a headroom factor h is a target utilization of 1/(1+h)
+func (p *BaseProblem) SetTargetUtilization(targetUtilization []float64) error {
+	if err := validateTargetUtilization(p.numServers, targetUtilization); err != nil {
+		return err
+	}
+	p.targetUtilization = targetUtilization
+	return nil
+}
+
+// unset target utilization, serving at most the rate of replicas
+func (p *BaseProblem) UnSetTargetUtilization() {
+	p.targetUtilization = nil
+}
+
+func (p *BaseProblem) GetTargetUtilization() []float64 {
+	return p.targetUtilization
+}
+
+// rate per replica of server and accelerator pair at the target utilization of the server
+func (p *BaseProblem) targetRate(i int, j int) float64 {
+	if p.targetUtilization == nil {
+		return p.ratePerReplica[i][j]
+	}
+	return p.targetUtilization[i] * p.ratePerReplica[i][j]
+}
+
+// least number of replicas of server, zero if none
+func (p *BaseProblem) minServerReplicas(i int) int {
+	if p.minReplicas == nil {
+		return 0
+	}
+	return p.minReplicas[i]
+}
+
+// most number of replicas of server, if any
+func (p *BaseProblem) maxServerReplicas(i int) (int, bool) {
+	if p.maxReplicas == nil || p.maxReplicas[i] < 0 {
+		return 0, false
+	}
+	return p.maxReplicas[i], true
+}
+
+// most number of replicas of server and accelerator pair, if any
+func (p *BaseProblem) maxReplicasOfPair(i int, j int) (int, bool) {
+	if p.maxPairReplicas == nil || p.maxPairReplicas[i][j] < 0 {
+		return 0, false
+	}
+	return p.maxPairReplicas[i][j], true
+}
+
 func (p *BaseProblem) SetSolverTimeout(t int) {
 	if t > 0 {
 		p.solverTimeoutSec = t
@@ -90,38 +180,307 @@ func (p *BaseProblem) GetSolverTimeout() int {
 	return p.solverTimeoutSec
 }
 
-// solve MILP problem using a timeout
-func (p *BaseProblem) solveWithTimeout() error {
+// no model variable, for an excluded server and accelerator pair
+const noVar model.Var = -1
+
+// add a model variable for each server and accelerator pair, named x_server<i>_acc<j>;
+// pairs with a zero rate per replica cannot serve any load and are excluded from the model
+func (p *BaseProblem) addPairVars(kind model.VarKind) {
+	p.addPairVarsExcept(kind, func(i int, j int) bool { return false })
+}
+
+// add a model variable for each server and accelerator pair, as addPairVars, also excluding the given pairs
+func (p *BaseProblem) addPairVarsExcept(kind model.VarKind, excluded func(i int, j int) bool) {
+	p.x = make([][]model.Var, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		p.x[i] = make([]model.Var, p.numAccelerators)
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.ratePerReplica[i][j] == 0 || excluded(i, j) {
+				p.x[i][j] = noVar
+				continue
+			}
+			p.x[i][j] = p.model.AddVar(fmt.Sprintf("x_server%d_acc%d", i, j), kind)
+		}
+	}
+}
+
+// add bounds on the number of replicas, when a model variable of a server and accelerator pair stands for a number of replicas:
+// variable bounds for pairs, and constraints named min_replicas_server<i> and max_replicas_server<i> for servers
+func (p *BaseProblem) addReplicaBounds() error {
+	for i := 0; i < p.numServers; i++ {
+		replicas := model.NewExpr()
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] == noVar {
+				continue
+			}
+			replicas.Add(1, p.x[i][j])
+			if maxPair, ok := p.maxReplicasOfPair(i, j); ok {
+				p.model.SetBounds(int(p.x[i][j]), 0, float64(maxPair))
+			}
+		}
+		if minServer := p.minServerReplicas(i); minServer > 0 {
+			if err := p.model.AddConstr(fmt.Sprintf("min_replicas_server%d", i), replicas, model.GE, float64(minServer)); err != nil {
+				return err
+			}
+		}
+		if maxServer, ok := p.maxServerReplicas(i); ok {
+			if err := p.model.AddConstr(fmt.Sprintf("max_replicas_server%d", i), replicas, model.LE, float64(maxServer)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add a count limit constraint for each accelerator type, named cap_type<k>, if limited;
+// a model variable of a server and accelerator pair stands for a number of instances of the accelerator
+func (p *BaseProblem) addCapConstraints(instances func(i int, j int) float64) error {
+	p.capRows = nil
+	if !p.isLimited {
+		return nil
+	}
+	p.capRows = make([]int, p.numAcceleratorTypes)
+	for k := 0; k < p.numAcceleratorTypes; k++ {
+		count := model.NewExpr()
+		for i := 0; i < p.numServers; i++ {
+			for j := 0; j < p.numAccelerators; j++ {
+				if p.acceleratorTypesMatrix[k][j] > 0 && p.x[i][j] != noVar {
+					count.Add(instances(i, j)*float64(p.acceleratorTypesMatrix[k][j]), p.x[i][j])
+				}
+			}
+		}
+		p.capRows[k] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("cap_type%d", k), count, model.LE, float64(p.unitsAvail[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cost of replicas, when a model variable of a server and accelerator pair stands for a number of replicas
+func (p *BaseProblem) replicaCost() *model.Expr {
+	cost := model.NewExpr()
+	for i := 0; i < p.numServers; i++ {
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] != noVar {
+				cost.Add(float64(p.numInstancesPerReplica[i][j])*p.instanceCost[j], p.x[i][j])
+			}
+		}
+	}
+	return cost
+}
+
+// obtain number of replicas, given the value of the model variable of each server and accelerator pair,
+// and calculate number of used accelerator instances and units, and served rates
+func (p *BaseProblem) setResults(replicas func(i int, j int, value float64) int) {
+	vars := p.solution.Values
+	p.numReplicas = make([][]int, p.numServers)
+	p.instancesUsed = make([]int, p.numAccelerators)
+	for i := 0; i < p.numServers; i++ {
+		p.numReplicas[i] = make([]int, p.numAccelerators)
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] == noVar {
+				continue
+			}
+			p.numReplicas[i][j] = replicas(i, j, vars[p.x[i][j]])
+			p.instancesUsed[j] += p.numReplicas[i][j] * p.numInstancesPerReplica[i][j]
+		}
+	}
+	p.calculateServedRates()
+
+	p.unitsUsed = make([]int, p.numAcceleratorTypes)
+	for k := 0; k < p.numAcceleratorTypes; k++ {
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.acceleratorTypesMatrix[k][j] > 0 {
+				p.unitsUsed[k] += p.instancesUsed[j] * p.acceleratorTypesMatrix[k][j]
+			}
+		}
+	}
+}
+
+// number of replicas given by the value of an integer model variable
+func roundReplicas(i int, j int, value float64) int {
+	return int(math.Round(value))
+}
+
+// add a binary selection variable for each server and accelerator pair with a model variable, named y_server<i>_acc<j>
+func (p *BaseProblem) addSelectionVars() {
+	p.y = make([][]model.Var, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		p.y[i] = make([]model.Var, p.numAccelerators)
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] == noVar {
+				p.y[i][j] = noVar
+				continue
+			}
+			p.y[i][j] = p.model.AddVar(fmt.Sprintf("y_server%d_acc%d", i, j), model.Binary)
+		}
+	}
+}
+
+// add constraints linking the number of replicas of server and accelerator pairs to their selection,
+// named link_server<i>_acc<j>: no replicas unless selected
+func (p *BaseProblem) addLinkConstraints() error {
+	for i := 0; i < p.numServers; i++ {
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] == noVar {
+				continue
+			}
+			link := model.NewExpr().Add(1, p.x[i][j]).Add(-float64(p.maxReplicasNeeded(i, j)), p.y[i][j])
+			if err := p.model.AddConstr(fmt.Sprintf("link_server%d_acc%d", i, j), link, model.LE, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// selected pairs of server
+func (p *BaseProblem) selection(i int) *model.Expr {
+	selected := model.NewExpr()
+	for j := 0; j < p.numAccelerators; j++ {
+		if p.y[i][j] != noVar {
+			selected.Add(1, p.y[i][j])
+		}
+	}
+	return selected
+}
+
+// most number of replicas of a server and accelerator pair (with a positive rate) needed:
+// enough for its arrival rate at the target utilization, or its least number of replicas, within the replica bounds
+func (p *BaseProblem) maxReplicasNeeded(i int, j int) int {
+	replicas := max(int(math.Ceil(p.arrivalRates[i]/p.targetRate(i, j))), p.minServerReplicas(i))
+	if maxServer, ok := p.maxServerReplicas(i); ok {
+		replicas = min(replicas, maxServer)
+	}
+	if maxPair, ok := p.maxReplicasOfPair(i, j); ok {
+		replicas = min(replicas, maxPair)
+	}
+	return replicas
+}
+
+// write model of problem in lp_solve LP format
+func (p *BaseProblem) WriteLP(w io.Writer) error {
+	if err := p.Setup(); err != nil {
+		return err
+	}
+	return p.model.WriteLP(w)
+}
+
+// write model of problem in free MPS format
+func (p *BaseProblem) WriteMPS(w io.Writer) error {
+	if err := p.Setup(); err != nil {
+		return err
+	}
+	return p.model.WriteMPS(w)
+}
+
+func (p *BaseProblem) SetBackend(backend Backend) {
+	if backend != nil {
+		p.backend = backend
+	}
+}
+
+func (p *BaseProblem) GetBackend() Backend {
+	return p.backend
+}
+
+// solve model with the backend using a timeout, aborting the solver when the context is done;
+// when the timeout expires the best incumbent solution found, if any, is kept (SUBOPTIMAL),
+// but when the context is done first the incumbent is discarded, and a TimeoutError returned
+func (p *BaseProblem) solveContext(ctx context.Context) error {
+	timeout := p.solverTimeout()
+	p.resetResults()
+
+	// skip the solver if the problem is certainly infeasible
+	if screening := p.Screen(); !screening.Feasible {
+		p.solutionType = INFEASIBLE
+		p.solutionTimeMsec = 0
+		return &ScreeningError{Screening: screening}
+	}
+
 	startTime := time.Now()
-	var err error
-	var timeoutSec int
+	sol, err := p.backend.Solve(ctx, p.model, timeout)
+	elapsed := time.Since(startTime)
+	p.solutionTimeMsec = elapsed.Milliseconds()
+	if err != nil {
+		p.solutionType = PROCFAIL
+		return err
+	}
+	p.solutionType = sol.Status
+
+	switch {
+	case ctx.Err() != nil && p.solutionType != OPTIMAL:
+		// cancelled by caller, discarding any incumbent
+		return &TimeoutError{Elapsed: elapsed, Err: ctx.Err(), SolutionType: p.solutionType}
+	case p.solutionType == TIMEOUT || p.solutionType == USERABORT:
+		// timeout expired before an incumbent was found
+		return &TimeoutError{Elapsed: elapsed, Err: context.DeadlineExceeded, SolutionType: p.solutionType}
+	case p.solutionType != OPTIMAL && p.solutionType != SUBOPTIMAL:
+		return &SolveError{SolutionType: p.solutionType, CplexStatus: sol.CplexStatus, SolverStatus: sol.SolverStatus}
+	}
+
+	p.solution = sol
+	p.objectiveValue = sol.Objective
+	p.bestBound = sol.BestBound
+	p.mipGap = relativeGap(sol.Objective, sol.BestBound)
+	return nil
+}
+
+// clear results of a previous solve, so that none are left if this solve fails
+func (p *BaseProblem) resetResults() {
+	p.solution = nil
+	p.objectiveValue = 0
+	p.bestBound = 0
+	p.mipGap = 0
+	p.numReplicas = nil
+	p.instancesUsed = nil
+	p.unitsUsed = nil
+	p.servedRates = nil
+	p.droppedRates = nil
+	p.utilization = nil
+}
+
+// relative tolerance on the primary objective value when minimizing cost as a secondary objective
+const secondaryTol = 1e-6
+
+// solve model again, minimizing cost while keeping the primary objective, optimized by the last solve,
+// at its value within a tolerance; the constraint on the primary objective is named name
+func (p *BaseProblem) solveCostSecondary(ctx context.Context, name string, primary *model.Expr, cost *model.Expr) error {
+	value := p.objectiveValue
+	tol := secondaryTol * math.Max(1, math.Abs(value))
+	ct, rhs := model.LE, value+tol
+	if p.model.Maximize {
+		ct, rhs = model.GE, value-tol
+	}
+	if err := p.model.AddConstr(name, primary, ct, rhs); err != nil {
+		return err
+	}
+	p.model.Maximize = false
+	if err := p.model.SetObjective(cost); err != nil {
+		return err
+	}
+	primaryTimeMsec := p.solutionTimeMsec
+	err := p.solveContext(ctx)
+	p.solutionTimeMsec += primaryTimeMsec
+	return err
+}
+
+// solver timeout, the default unless overridden
+func (p *BaseProblem) solverTimeout() time.Duration {
+	timeoutSec := config.DefaultSolverTimeout
 	if p.solverTimeoutSec > 0 {
 		timeoutSec = p.solverTimeoutSec
-	} else {
-		timeoutSec = config.DefaultSolverTimeout
-	}
-	timeout := time.Duration(timeoutSec) * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	// solution routine
-	go func() {
-		// p.lp.SetVerboseLevel(golp.DETAILED)
-		p.solutionType = p.lp.Solve()
-		if p.solutionType != golp.OPTIMAL && p.solutionType != golp.SUBOPTIMAL {
-			err = fmt.Errorf("LP solve failed; solutionType=%s", p.solutionType.String())
-		}
-		cancel()
-	}()
+	}
+	return time.Duration(timeoutSec) * time.Second
+}
 
-	// wait for solve to finish or timeout
-	<-ctx.Done()
-	endTime := time.Now()
-	p.solutionTimeMsec = endTime.Sub(startTime).Milliseconds()
-	return err
+// relative gap between an incumbent objective value and a bound
+func relativeGap(objective float64, bound float64) float64 {
+	return math.Abs(objective-bound) / (1e-10 + math.Abs(objective))
 }
 
-func (p *BaseProblem) GetSolutionType() golp.SolutionType {
+func (p *BaseProblem) GetSolutionType() SolutionType {
 	return p.solutionType
 }
 
@@ -133,6 +492,14 @@ func (p *BaseProblem) GetObjectiveValue() float64 {
 	return p.objectiveValue
 }
 
+func (p *BaseProblem) GetBestBound() float64 {
+	return p.bestBound
+}
+
+func (p *BaseProblem) GetMIPGap() float64 {
+	return p.mipGap
+}
+
 func (p *BaseProblem) GetNumReplicas() [][]int {
 	return p.numReplicas
 }
@@ -144,3 +511,46 @@ func (p *BaseProblem) GetInstancesUsed() []int {
 func (p *BaseProblem) GetUnitsUsed() []int {
 	return p.unitsUsed
 }
+
+func (p *BaseProblem) GetServedRates() []float64 {
+	return p.servedRates
+}
+
+func (p *BaseProblem) GetDroppedRates() []float64 {
+	return p.droppedRates
+}
+
+func (p *BaseProblem) GetUtilization() []float64 {
+	return p.utilization
+}
+
+// calculate served and dropped arrival rates of servers, and utilization, given the number of replicas;
+// replicas serve at most their rate at the target utilization
+func (p *BaseProblem) calculateServedRates() {
+	p.servedRates = make([]float64, p.numServers)
+	p.droppedRates = make([]float64, p.numServers)
+	p.utilization = make([]float64, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		_, targetCapacity := p.serverCapacity(i)
+		p.setServedRate(i, math.Min(p.arrivalRates[i], targetCapacity))
+	}
+}
+
+// set served rate of server, and the resulting dropped rate and utilization
+func (p *BaseProblem) setServedRate(i int, servedRate float64) {
+	p.servedRates[i] = servedRate
+	p.droppedRates[i] = p.arrivalRates[i] - servedRate
+	p.utilization[i] = 0
+	if capacity, _ := p.serverCapacity(i); capacity > 0 {
+		p.utilization[i] = servedRate / capacity
+	}
+}
+
+// rate of the replicas of server, at full and at target utilization
+func (p *BaseProblem) serverCapacity(i int) (capacity float64, targetCapacity float64) {
+	for j := 0; j < p.numAccelerators; j++ {
+		capacity += float64(p.numReplicas[i][j]) * p.ratePerReplica[i][j]
+		targetCapacity += float64(p.numReplicas[i][j]) * p.targetRate(i, j)
+	}
+	return capacity, targetCapacity
+}
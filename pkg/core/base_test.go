@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// backend failing every solve
+type failingBackend struct{}
+
+func (b *failingBackend) Name() string {
+	return "failing"
+}
+
+func (b *failingBackend) Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error) {
+	return nil, &BackendError{Backend: b.Name(), Err: errors.New("solver crashed")}
+}
+
+// a failed solve leaves no results of a previous solve
+func TestSolveResetsResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		fail   func(p *MultiAssignProblem) error
+		status SolutionType
+		err    error
+	}{
+		{
+			name: "screening",
+			fail: func(p *MultiAssignProblem) error {
+				return p.SetLimited(1, []int{1}, [][]int{{1}})
+			},
+			status: INFEASIBLE, err: ErrInfeasible,
+		},
+		{
+			name: "backend",
+			fail: func(p *MultiAssignProblem) error {
+				p.SetBackend(&failingBackend{})
+				return nil
+			},
+			status: PROCFAIL, err: ErrBackend,
+		},
+		{
+			name: "solver",
+			fail: func(p *MultiAssignProblem) error {
+				p.SetBackend(&statusBackend{status: INFEASIBLE})
+				return nil
+			},
+			status: INFEASIBLE, err: ErrInfeasible,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := createDiagnoseProblem(t, 6)
+			p.SetBackend(NewGoBackend())
+			if err := p.Solve(); err != nil {
+				t.Fatal(err)
+			}
+			if p.GetObjectiveValue() == 0 || p.GetNumReplicas() == nil {
+				t.Fatalf("no results of first solve")
+			}
+
+			if err := tt.fail(p); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Solve(); !errors.Is(err, tt.err) {
+				t.Fatalf("Solve() error = %v, want %v", err, tt.err)
+			}
+			if p.GetSolutionType() != tt.status {
+				t.Errorf("solution type = %v, want %v", p.GetSolutionType(), tt.status)
+			}
+			if p.GetObjectiveValue() != 0 || p.GetBestBound() != 0 || p.GetMIPGap() != 0 {
+				t.Errorf("objective value %v, best bound %v, gap %v left from previous solve",
+					p.GetObjectiveValue(), p.GetBestBound(), p.GetMIPGap())
+			}
+			if p.GetNumReplicas() != nil || p.GetInstancesUsed() != nil || p.GetUnitsUsed() != nil ||
+				p.GetServedRates() != nil || p.GetDroppedRates() != nil || p.GetUtilization() != nil {
+				t.Errorf("replicas %v, instances %v, units %v, served %v, dropped %v, utilization %v left from previous solve",
+					p.GetNumReplicas(), p.GetInstancesUsed(), p.GetUnitsUsed(),
+					p.GetServedRates(), p.GetDroppedRates(), p.GetUtilization())
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/llm-inferno/lpsolve/pkg/config"
+)
+
+// size of the benchmarked problems, that of our fleet
+const (
+	benchServers      = 2000
+	benchAccelerators = 40
+)
+
+// random limited problem, one accelerator type per accelerator, and its base holding the model
+func createBenchProblem(b *testing.B, problemType config.ProblemType, numServers int, numAccelerators int) (Problem, *BaseProblem) {
+	b.Helper()
+	rng := rand.New(rand.NewSource(1))
+	instanceCost := make([]float64, numAccelerators)
+	for j := range instanceCost {
+		instanceCost[j] = 0.5 + 10*rng.Float64()
+	}
+	numInstancesPerReplica := make([][]int, numServers)
+	ratePerReplica := make([][]float64, numServers)
+	arrivalRates := make([]float64, numServers)
+	for i := 0; i < numServers; i++ {
+		numInstancesPerReplica[i] = make([]int, numAccelerators)
+		ratePerReplica[i] = make([]float64, numAccelerators)
+		for j := 0; j < numAccelerators; j++ {
+			numInstancesPerReplica[i][j] = 1 + rng.Intn(8)
+			ratePerReplica[i][j] = 0.1 + 3*rng.Float64()
+		}
+		arrivalRates[i] = 1 + 50*rng.Float64()
+	}
+	unitsAvail := make([]int, numAccelerators)
+	acceleratorTypesMatrix := make([][]int, numAccelerators)
+	for k := 0; k < numAccelerators; k++ {
+		unitsAvail[k] = 100 * numServers
+		acceleratorTypesMatrix[k] = make([]int, numAccelerators)
+		acceleratorTypesMatrix[k][k] = 1
+	}
+
+	var p Problem
+	var base *BaseProblem
+	switch problemType {
+	case config.SINGLE:
+		single, err := CreateSingleAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+		if err != nil {
+			b.Fatal(err)
+		}
+		p, base = single, &single.BaseProblem
+	case config.MULTI:
+		multi, err := CreateMultiAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+		if err != nil {
+			b.Fatal(err)
+		}
+		p, base = multi, &multi.BaseProblem
+	default:
+		b.Fatalf("unknown problem type: %s", problemType)
+	}
+	if err := p.SetLimited(numAccelerators, unitsAvail, acceleratorTypesMatrix); err != nil {
+		b.Fatal(err)
+	}
+	return p, base
+}
+
+// setup of the solver-neutral model
+func BenchmarkSetup(b *testing.B) {
+	for _, problemType := range []config.ProblemType{config.SINGLE, config.MULTI} {
+		b.Run(problemType.String(), func(b *testing.B) {
+			p, _ := createBenchProblem(b, problemType, benchServers, benchAccelerators)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if err := p.Setup(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
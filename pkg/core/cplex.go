@@ -1,193 +1,119 @@
 package core
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
-	"strconv"
-	"strings"
-	"time"
 
-	"github.com/draffensperger/golp"
-	"github.com/llm-inferno/lpsolve/pkg/utils"
+	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/opl"
 )
 
 const (
 	DefaultModelFileName  = "inferno.mod"
-	DefaultDataFileName   = "data.dat"
 	DefaultOutputFileName = "out.txt"
 
 	DefaultOPLCommand = "oplrun"
 )
 
-// path includes '/' at the end
-var (
-	ModelPath = os.Getenv("CPLEX_MODEL_PATH")
-	DataPath  = os.Getenv("CPLEX_DATA_PATH")
-)
+// default parent directory of the per-solve working directories, with generated model and output files;
+// the system temporary directory if empty
+var DataPath = os.Getenv("CPLEX_DATA_PATH")
+
+// OPL command, may be overridden (e.g. by a fake for testing)
+var OPLCommand = getEnvOrDefault("CPLEX_OPL_COMMAND", DefaultOPLCommand)
 
-// Optimization problem solved by CPLEX
+// Optimization problem solved by CPLEX:
+// the OPL model is generated from the model of the problem type (SINGLE, MULTI, THROUGHPUT, FAIRNESS, or HYBRID)
 type CplexProblem struct {
-	BaseProblem
+	Problem
 
-	dataFileName   string
-	modelFileName  string
-	outputFileName string
+	backend *CplexBackend
 }
 
-func CreateCplexProblem(numServers int, numAccelerators int, instanceCost []float64, numInstancesPerReplica [][]int,
-	ratePerReplica [][]float64, arrivalRates []float64) (*CplexProblem, error) {
-	bp, err := CreateBaseProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
-		ratePerReplica, arrivalRates)
+func CreateCplexProblem(problemType config.ProblemType, numServers int, numAccelerators int, instanceCost []float64,
+	numInstancesPerReplica [][]int, ratePerReplica [][]float64, arrivalRates []float64) (*CplexProblem, error) {
+	var p Problem
+	var err error
+	switch problemType {
+	case config.SINGLE:
+		p, err = CreateSingleAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	case config.MULTI:
+		p, err = CreateMultiAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	case config.THROUGHPUT:
+		p, err = CreateThroughputProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	case config.FAIRNESS:
+		p, err = CreateFairnessProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	case config.HYBRID:
+		p, err = CreateHybridAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+			ratePerReplica, arrivalRates)
+	default:
+		return nil, fmt.Errorf("unknown problem type: %s", problemType)
+	}
 	if err != nil {
 		return nil, err
 	}
-	p := &CplexProblem{BaseProblem: *bp,
-		dataFileName:   DefaultDataFileName,
-		modelFileName:  DefaultModelFileName,
-		outputFileName: DefaultOutputFileName}
-
-	p.BaseProblem.Setup = p.Setup
-	p.BaseProblem.Solve = p.Solve
-	return p, nil
-}
 
-// generate data file for CPLEX
-func (p *CplexProblem) Setup() error {
-	dataString := p.generateDataFile()
-	dataFile := DataPath + p.dataFileName
-	return os.WriteFile(dataFile, []byte(dataString), 0644)
+	backend := NewCplexBackend()
+	backend.SetBaseDir(DataPath)
+	p.SetBackend(backend)
+	return &CplexProblem{Problem: p, backend: backend}, nil
 }
 
-// solve problem using CPLEX by running OPL on data
-func (p *CplexProblem) Solve() error {
-	startTime := time.Now()
-
-	// generate data file
-	if err := p.Setup(); err != nil {
-		fmt.Println(err)
-		return err
-	}
-
-	// invoke OPL on model
-	modelFile := ModelPath + p.modelFileName
-	dataFile := DataPath + p.dataFileName
-	cmdRun := exec.Command(DefaultOPLCommand, modelFile, dataFile)
-	stdoutRun, err := cmdRun.Output()
-	if err != nil {
-		fmt.Println(err.Error())
-		return err
-	}
-	// fmt.Println(string(stdoutRun))
-	outFile := DataPath + p.outputFileName
-	os.WriteFile(outFile, stdoutRun, 0644)
-
-	// extract objective value
-	obj := "grep OBJECTIVE: " + outFile + " | awk '{print $2}'"
-	cmdObj := exec.Command("bash", "-c", obj)
-	stdoutObj, err := cmdObj.Output()
-	if err != nil {
-		fmt.Println(err.Error())
-		return err
-	}
-	stdoutObj = stdoutObj[:len(stdoutObj)-1] // trim end of line
-	objective, _ := strconv.ParseFloat(string(stdoutObj), 64)
-	// fmt.Printf("objective=%v \n", objective)
-	p.objectiveValue = objective
-
-	// obtain number of replicas and number of used accelerator units
-	sed := "sed -n '/numReplicas/,/^$/p' " + outFile
-	cmdSed := exec.Command("bash", "-c", sed)
-	stdoutSed, err := cmdSed.Output()
-	if err != nil {
-		fmt.Println(err.Error())
-		return err
-	}
-	nrString := string(stdoutSed)
-
-	nrString = strings.ReplaceAll(nrString, "\n", "")
-	nrString = strings.ReplaceAll(nrString, "    ", "")
-	nrString = strings.Replace(nrString, "numReplicas = ", "", 1)
-	nrString = strings.Replace(nrString, "[", "", -1)
-	nrString = strings.Replace(nrString, "]", "", -1)
-	nrStringArray := strings.Split(nrString, " ")
-
-	p.numReplicas = make([][]int, p.numServers)
-	p.instancesUsed = make([]int, p.numAccelerators)
-	for i := 0; i < p.numServers; i++ {
-		p.numReplicas[i] = make([]int, p.numAccelerators)
-		v0 := i * p.numAccelerators // begin index
-		for j := 0; j < p.numAccelerators; j++ {
-			p.numReplicas[i][j], _ = strconv.Atoi(nrStringArray[v0+j])
-			p.instancesUsed[j] += p.numReplicas[i][j] * p.numInstancesPerReplica[i][j]
-		}
-	}
-
-	p.solutionType = golp.OPTIMAL
-
-	// calculate number of used accelerator units
-	p.unitsUsed = make([]int, p.numAcceleratorTypes)
-	for k := 0; k < p.numAcceleratorTypes; k++ {
-		for j := 0; j < p.numAccelerators; j++ {
-			if p.acceleratorTypesMatrix[k][j] > 0 {
-				p.unitsUsed[k] += p.instancesUsed[j] * p.acceleratorTypesMatrix[k][j]
-			}
-		}
-	}
-
-	endTime := time.Now()
-	p.solutionTimeMsec = endTime.Sub(startTime).Milliseconds()
-	return nil
+// the problem is always solved by CPLEX
+func (p *CplexProblem) SetBackend(backend Backend) {
 }
 
-func (p *BaseProblem) generateDataFile() string {
-	var b bytes.Buffer
-
-	b.WriteString(utils.Pretty("numServers", p.numServers) + "\n")
-	b.WriteString(utils.Pretty("numAccelerators", p.numAccelerators) + "\n")
-	if p.isLimited {
-		b.WriteString(utils.Pretty("numAcceleratorTypes", p.numAcceleratorTypes) + "\n")
-	}
-	b.WriteString("\n")
-
-	b.WriteString(utils.Pretty1D("arrivalRates", p.arrivalRates) + "\n")
-	b.WriteString(utils.Pretty1D("instanceCost", p.instanceCost) + "\n")
-	if p.isLimited {
-		b.WriteString(utils.Pretty1D("unitsAvail", p.unitsAvail) + "\n")
-	}
-	b.WriteString("\n")
+// set parent directory of the per-solve working directories
+func (p *CplexProblem) SetBaseDir(baseDir string) {
+	p.backend.SetBaseDir(baseDir)
+}
 
-	b.WriteString(utils.Pretty2D("numInstancesPerReplica", p.numInstancesPerReplica) + "\n")
-	b.WriteString(utils.Pretty2D("ratePerReplica", p.ratePerReplica) + "\n")
-	if p.isLimited {
-		b.WriteString(utils.Pretty2D("acceleratorTypesMatrix", p.acceleratorTypesMatrix) + "\n")
-	}
-	b.WriteString("\n")
+func (p *CplexProblem) GetBaseDir() string {
+	return p.backend.GetBaseDir()
+}
 
-	return b.String()
+// keep working directories, with generated model and output files, after solving
+func (p *CplexProblem) SetKeepFiles(keepFiles bool) {
+	p.backend.SetKeepFiles(keepFiles)
 }
 
-func (p *CplexProblem) SetDataFileName(dataFileName string) {
-	p.dataFileName = dataFileName
+func (p *CplexProblem) GetKeepFiles() bool {
+	return p.backend.GetKeepFiles()
 }
 
-func (p *CplexProblem) GetDataFileName() string {
-	return p.dataFileName
+// working directory of the last solve, empty unless files are kept
+func (p *CplexProblem) GetLastRunDir() string {
+	return p.backend.GetLastRunDir()
 }
 
 func (p *CplexProblem) SetModelFileName(modelFileName string) {
-	p.modelFileName = modelFileName
+	p.backend.SetModelFileName(modelFileName)
 }
 
 func (p *CplexProblem) GetModelFileName() string {
-	return p.modelFileName
+	return p.backend.GetModelFileName()
 }
 
 func (p *CplexProblem) SetOutputFileName(outputFileName string) {
-	p.outputFileName = outputFileName
+	p.backend.SetOutputFileName(outputFileName)
 }
 
 func (p *CplexProblem) GetOutputFileName() string {
-	return p.outputFileName
+	return p.backend.GetOutputFileName()
+}
+
+// parsed output of the last OPL run, including solve statistics
+func (p *CplexProblem) GetOPLOutput() *opl.Output {
+	return p.backend.GetOPLOutput()
+}
+
+func getEnvOrDefault(name string, defaultValue string) string {
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		return value
+	}
+	return defaultValue
 }
@@ -0,0 +1,333 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+	"github.com/llm-inferno/lpsolve/pkg/opl"
+)
+
+// OPL flow control: solve, print objective and variable values (post processing) if a solution is found,
+// and always print status and statistics, see opl.Output
+const oplMain = `main {
+  thisOplModel.generate();
+  if (cplex.solve()) {
+    writeln("OBJECTIVE: " + cplex.getObjValue());
+    writeln("BEST_BOUND: " + cplex.getBestObjValue());
+    thisOplModel.postProcess();
+  } else {
+    writeln("<<< no solution");
+  }
+  writeln("STATUS: " + cplex.getCplexStatus());
+  writeln("ITERATIONS: " + cplex.getNiterations());
+  writeln("NODES: " + cplex.getNnodes());
+}
+`
+
+// Backend solving models with CPLEX by running OPL on a generated model;
+// each solve uses its own working directory, so concurrent solves do not clobber each other's files
+type CplexBackend struct {
+	baseDir        string // parent of the working directories, the system temporary directory if empty
+	keepFiles      bool   // keep working directories after solving, for debugging
+	modelFileName  string
+	outputFileName string
+
+	mutex   sync.Mutex
+	output  *opl.Output // parsed output of last OPL run
+	lastDir string      // working directory of last OPL run, if kept
+}
+
+func NewCplexBackend() *CplexBackend {
+	return &CplexBackend{
+		modelFileName:  DefaultModelFileName,
+		outputFileName: DefaultOutputFileName,
+	}
+}
+
+func (b *CplexBackend) Name() string {
+	return "cplex"
+}
+
+// solve model with CPLEX within the time limit, returning its incumbent when the limit is hit;
+// OPL and its child processes are killed when the context is done, or when OPL overruns the time limit
+func (b *CplexBackend) Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error) {
+	dir, err := os.MkdirTemp(b.baseDir, "cplex-")
+	if err != nil {
+		return nil, err
+	}
+	if b.keepFiles {
+		b.mutex.Lock()
+		b.lastDir = dir
+		b.mutex.Unlock()
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	modelFile := filepath.Join(dir, b.modelFileName)
+	if err := os.WriteFile(modelFile, []byte(generateOPLModel(m, timeout)), 0644); err != nil {
+		return nil, err
+	}
+
+	// CPLEX stops by itself at the time limit, the context deadline only guards against overrun
+	runCtx, cancel := context.WithTimeout(ctx, timeout+solverTimeoutGrace)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, OPLCommand, modelFile)
+	setKillProcessTree(cmd)
+	cmd.WaitDelay = time.Second
+	stdout, err := cmd.Output()
+	if err != nil {
+		if runCtx.Err() != nil {
+			return &Solution{Status: TIMEOUT}, nil
+		}
+		backendErr := &BackendError{Backend: b.Name(), Err: err}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			backendErr.Stderr = string(exitErr.Stderr)
+		}
+		return nil, backendErr
+	}
+	outFile := filepath.Join(dir, b.outputFileName)
+	if err := os.WriteFile(outFile, stdout, 0644); err != nil {
+		return nil, err
+	}
+
+	out, err := opl.Parse(bytes.NewReader(stdout))
+	if err != nil {
+		return nil, &BackendError{Backend: b.Name(), Err: fmt.Errorf("%s: %w", outFile, err)}
+	}
+	b.mutex.Lock()
+	b.output = out
+	b.mutex.Unlock()
+	sol, err := oplSolution(out, m)
+	if err != nil {
+		return nil, &BackendError{Backend: b.Name(), Err: fmt.Errorf("%s: %w", outFile, err)}
+	}
+	return sol, nil
+}
+
+// set parent directory of the per-solve working directories
+func (b *CplexBackend) SetBaseDir(baseDir string) {
+	b.baseDir = baseDir
+}
+
+func (b *CplexBackend) GetBaseDir() string {
+	return b.baseDir
+}
+
+// keep working directories, with generated model and output files, after solving
+func (b *CplexBackend) SetKeepFiles(keepFiles bool) {
+	b.keepFiles = keepFiles
+}
+
+func (b *CplexBackend) GetKeepFiles() bool {
+	return b.keepFiles
+}
+
+// working directory of the last solve, empty unless files are kept
+func (b *CplexBackend) GetLastRunDir() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.lastDir
+}
+
+func (b *CplexBackend) SetModelFileName(modelFileName string) {
+	b.modelFileName = modelFileName
+}
+
+func (b *CplexBackend) GetModelFileName() string {
+	return b.modelFileName
+}
+
+func (b *CplexBackend) SetOutputFileName(outputFileName string) {
+	b.outputFileName = outputFileName
+}
+
+func (b *CplexBackend) GetOutputFileName() string {
+	return b.outputFileName
+}
+
+// parsed output of the last OPL run, including solve statistics
+func (b *CplexBackend) GetOPLOutput() *opl.Output {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.output
+}
+
+// generate OPL model text for model, with CPLEX time limit
+func generateOPLModel(m *model.Model, timeout time.Duration) string {
+	var b bytes.Buffer
+	b.WriteString("/*********************************************\n")
+	b.WriteString(" * OPL model generated by lpsolve\n")
+	b.WriteString(" *********************************************/\n\n")
+
+	// solver parameters
+	b.WriteString("execute {\n")
+	fmt.Fprintf(&b, "  cplex.tilim = %s;\n", oplFloat(timeout.Seconds()))
+	b.WriteString("}\n\n")
+
+	// decision variables
+	for k, v := range m.Vars {
+		name := m.VarName(k)
+		switch v.Kind {
+		case model.Binary:
+			fmt.Fprintf(&b, "dvar boolean %s;\n", name)
+		case model.Integer:
+			fmt.Fprintf(&b, "dvar int %s in %s..%s;\n", name, oplIntBound(math.Ceil(v.Lower)), oplIntBound(math.Floor(v.Upper)))
+		default:
+			fmt.Fprintf(&b, "dvar float %s in %s..%s;\n", name, oplFloatBound(v.Lower), oplFloatBound(v.Upper))
+		}
+	}
+	b.WriteString("\n")
+
+	// objective function
+	objective := make([]model.Entry, 0)
+	for k, c := range m.Objective {
+		if c != 0 {
+			objective = append(objective, model.Entry{Col: k, Val: c})
+		}
+	}
+	if m.Maximize {
+		b.WriteString("maximize\n")
+	} else {
+		b.WriteString("minimize\n")
+	}
+	fmt.Fprintf(&b, "  %s;\n\n", oplLinearExpr(m, objective))
+
+	// constraints
+	b.WriteString("subject to {\n")
+	for r, c := range m.Constraints {
+		op := map[model.ConstraintType]string{model.LE: "<=", model.GE: ">=", model.EQ: "=="}[c.Type]
+		fmt.Fprintf(&b, "  %s: %s %s %s;\n", m.ConstraintName(r), oplLinearExpr(m, c.Row), op, oplFloat(c.RHS))
+	}
+	b.WriteString("};\n\n")
+
+	// solution output
+	b.WriteString("execute {\n")
+	for k := range m.Vars {
+		name := m.VarName(k)
+		fmt.Fprintf(&b, "  writeln(\"VALUE %s \", %s);\n", name, name)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(oplMain)
+	return b.String()
+}
+
+// extract solution of model from OPL output
+func oplSolution(out *opl.Output, m *model.Model) (*Solution, error) {
+	sol := &Solution{Status: OPTIMAL}
+	if out.HasStatus {
+		sol.Status = cplexSolutionType(out.Status)
+		sol.CplexStatus = out.Status
+		sol.SolverStatus = fmt.Sprintf("CPLEX status %d (%s)", out.Status, opl.StatusName(out.Status))
+	} else if out.NoSolution {
+		return nil, errors.New("no solution and no status in OPL output")
+	}
+	if sol.Status != OPTIMAL && sol.Status != SUBOPTIMAL {
+		return sol, nil
+	}
+	if !out.HasObjective {
+		return nil, errors.New("no objective value in OPL output")
+	}
+
+	sol.Objective = out.Objective
+	sol.Values = make([]float64, m.NumVars())
+	for k := range m.Vars {
+		v, ok := out.Values[m.VarName(k)]
+		if !ok {
+			return nil, fmt.Errorf("no value of variable %s in OPL output", m.VarName(k))
+		}
+		sol.Values[k] = v
+	}
+	sol.BestBound = sol.Objective
+	if out.HasBestBound {
+		sol.BestBound = out.BestBound
+	}
+	return sol, nil
+}
+
+// linear expression: c1*x1 + c2*x2 + ...
+func oplLinearExpr(m *model.Model, entries []model.Entry) string {
+	if len(entries) == 0 {
+		if m.NumVars() > 0 {
+			return "0*" + m.VarName(0)
+		}
+		return "0"
+	}
+	var b strings.Builder
+	for i, e := range entries {
+		val := e.Val
+		if i > 0 {
+			if val < 0 {
+				b.WriteString(" - ")
+				val = -val
+			} else {
+				b.WriteString(" + ")
+			}
+		}
+		fmt.Fprintf(&b, "%s*%s", oplFloat(val), m.VarName(e.Col))
+	}
+	return b.String()
+}
+
+func oplFloatBound(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "infinity"
+	case math.IsInf(v, -1):
+		return "-infinity"
+	default:
+		return oplFloat(v)
+	}
+}
+
+func oplIntBound(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "maxint"
+	case math.IsInf(v, -1):
+		return "-maxint"
+	default:
+		return strconv.Itoa(int(v))
+	}
+}
+
+// map CPLEX status to solution type
+func cplexSolutionType(status int) SolutionType {
+	switch status {
+	case opl.StatusOptimal, opl.StatusMIPOptimal, opl.StatusMIPOptimalTol:
+		return OPTIMAL
+	case opl.StatusMIPSolLim, opl.StatusMIPNodeLimFeas, opl.StatusMIPTimeLimFeas, opl.StatusMIPFailFeas,
+		opl.StatusMIPMemLimFeas, opl.StatusMIPAbortFeas:
+		return SUBOPTIMAL
+	case opl.StatusInfeasible, opl.StatusInfOrUnbd, opl.StatusMIPInfeasible, opl.StatusMIPInfOrUnbd:
+		return INFEASIBLE
+	case opl.StatusUnbounded, opl.StatusMIPUnbounded:
+		return UNBOUNDED
+	case opl.StatusAbortTimeLim, opl.StatusMIPTimeLimInfeas:
+		return TIMEOUT
+	case opl.StatusAbortUser, opl.StatusMIPAbortInfeas:
+		return USERABORT
+	case opl.StatusMIPNodeLimInfeas:
+		return NOFEASFOUND
+	case opl.StatusMIPMemLimInfeas:
+		return NOMEMORY
+	default:
+		return NUMFAILURE
+	}
+}
+
+func oplFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,308 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+	"github.com/llm-inferno/lpsolve/pkg/opl"
+)
+
+// run OPL commands with the fake oplrun for the duration of the test
+func useFakeOPL(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("fake oplrun needs bash")
+	}
+	fake, err := filepath.Abs("../../cplex/fake/oplrun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CPLEX_OPL_COMMAND", fake)
+	saved := OPLCommand
+	OPLCommand = getEnvOrDefault("CPLEX_OPL_COMMAND", DefaultOPLCommand)
+	t.Cleanup(func() { OPLCommand = saved })
+}
+
+// max x + y, subject to x + 2 y <= 4, with integer x and binary y
+func cplexTestModel() *model.Model {
+	m := model.NewModel(0)
+	x := m.AddBoundedVar("x", model.Integer, 0, 10)
+	y := m.AddVar("y", model.Binary)
+	m.SetMaximize()
+	m.SetObjective(model.NewExpr().Add(1, x).Add(1, y))
+	m.AddConstr("cap", model.NewExpr().Add(1, x).Add(2, y), model.LE, 4)
+	return m
+}
+
+func TestCplexBackendSolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		status    SolutionType
+		cplex     int
+		objective float64
+		values    []float64
+	}{
+		{
+			name:   "optimal",
+			env:    map[string]string{"FAKE_OPL_OBJECTIVE": "4", "FAKE_OPL_VALUE": "2"},
+			status: OPTIMAL, cplex: opl.StatusMIPOptimal, objective: 4, values: []float64{2, 2},
+		},
+		{
+			name:   "incumbent at time limit",
+			env:    map[string]string{"FAKE_OPL_OBJECTIVE": "3", "FAKE_OPL_VALUE": "1", "FAKE_OPL_STATUS": "107"},
+			status: SUBOPTIMAL, cplex: opl.StatusMIPTimeLimFeas, objective: 3, values: []float64{1, 1},
+		},
+		{
+			name:   "infeasible",
+			env:    map[string]string{"FAKE_OPL_STATUS": "103"},
+			status: INFEASIBLE, cplex: opl.StatusMIPInfeasible,
+		},
+		{
+			name:   "unbounded",
+			env:    map[string]string{"FAKE_OPL_STATUS": "118"},
+			status: UNBOUNDED, cplex: opl.StatusMIPUnbounded,
+		},
+		{
+			name:   "no solution at time limit",
+			env:    map[string]string{"FAKE_OPL_STATUS": "108"},
+			status: TIMEOUT, cplex: opl.StatusMIPTimeLimInfeas,
+		},
+	}
+	useFakeOPL(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			b := NewCplexBackend()
+			sol, err := b.Solve(context.Background(), cplexTestModel(), 10*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sol.Status != tt.status || sol.CplexStatus != tt.cplex {
+				t.Fatalf("status = %v (CPLEX %d), want %v (CPLEX %d)", sol.Status, sol.CplexStatus, tt.status, tt.cplex)
+			}
+			if b.GetOPLOutput().Status != tt.cplex {
+				t.Errorf("OPL output status = %d, want %d", b.GetOPLOutput().Status, tt.cplex)
+			}
+			if tt.values == nil {
+				return
+			}
+			if sol.Objective != tt.objective {
+				t.Errorf("objective = %v, want %v", sol.Objective, tt.objective)
+			}
+			for k, want := range tt.values {
+				if sol.Values[k] != want {
+					t.Errorf("value[%d] = %v, want %v", k, sol.Values[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCplexBackendErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string // printed by the fake instead of the generated output, if not empty
+		env    map[string]string
+	}{
+		{name: "exit code", env: map[string]string{"FAKE_OPL_EXIT": "1"}},
+		{name: "malformed output", output: "OBJECTIVE: 4\nVALUE x two\nSTATUS: 101\n"},
+		{name: "missing value", output: "OBJECTIVE: 4\nVALUE x 2\nSTATUS: 101\n"},
+		{name: "missing objective", output: "VALUE x 2\nVALUE y 1\nSTATUS: 101\n"},
+		{name: "missing status", output: "<<< no solution\n"},
+	}
+	useFakeOPL(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if tt.output != "" {
+				outFile := filepath.Join(t.TempDir(), "out.txt")
+				if err := os.WriteFile(outFile, []byte(tt.output), 0644); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("FAKE_OPL_OUTPUT", outFile)
+			}
+			_, err := NewCplexBackend().Solve(context.Background(), cplexTestModel(), 10*time.Second)
+			var backendErr *BackendError
+			if !errors.As(err, &backendErr) || !errors.Is(err, ErrBackend) {
+				t.Fatalf("Solve() error = %v, want *BackendError", err)
+			}
+			if backendErr.Backend != "cplex" {
+				t.Errorf("backend = %q, want %q", backendErr.Backend, "cplex")
+			}
+		})
+	}
+}
+
+func TestCplexBackendKeepFiles(t *testing.T) {
+	useFakeOPL(t)
+	b := NewCplexBackend()
+	b.SetBaseDir(t.TempDir())
+	b.SetKeepFiles(true)
+	if _, err := b.Solve(context.Background(), cplexTestModel(), 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	dir := b.GetLastRunDir()
+	for _, name := range []string{DefaultModelFileName, DefaultOutputFileName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestCplexProblemSolve(t *testing.T) {
+	useFakeOPL(t)
+	// every variable is one: a single replica per pair serves all demand
+	t.Setenv("FAKE_OPL_VALUE", "1")
+	for _, problemType := range []config.ProblemType{config.SINGLE, config.MULTI, config.HYBRID} {
+		t.Run(problemType.String(), func(t *testing.T) {
+			p, err := CreateCplexProblem(problemType, 2, 2, []float64{1, 2}, [][]int{{1, 1}, {1, 1}},
+				[][]float64{{10, 10}, {10, 10}}, []float64{5, 5})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetBaseDir(t.TempDir())
+			if err := p.Solve(); err != nil {
+				t.Fatal(err)
+			}
+			if p.GetSolutionType() != OPTIMAL {
+				t.Errorf("solution type = %v, want %v", p.GetSolutionType(), OPTIMAL)
+			}
+			if got := p.GetOPLOutput().Status; got != opl.StatusMIPOptimal {
+				t.Errorf("OPL output status = %d, want %d", got, opl.StatusMIPOptimal)
+			}
+		})
+	}
+}
+
+func TestCplexBackendTimeLimit(t *testing.T) {
+	useFakeOPL(t)
+	// the fake sleeps until the time limit of the model, and reports its incumbent
+	t.Setenv("FAKE_OPL_SLEEP", "60")
+	t.Setenv("FAKE_OPL_VALUE", "1")
+	start := time.Now()
+	sol, err := NewCplexBackend().Solve(context.Background(), cplexTestModel(), 500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Status != SUBOPTIMAL || sol.CplexStatus != opl.StatusMIPTimeLimFeas {
+		t.Errorf("status = %v (CPLEX %d), want %v (CPLEX %d)", sol.Status, sol.CplexStatus, SUBOPTIMAL, opl.StatusMIPTimeLimFeas)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond+solverTimeoutGrace {
+		t.Errorf("solve took %v, beyond the time limit and grace period", elapsed)
+	}
+}
+
+func TestCplexProblemHung(t *testing.T) {
+	useFakeOPL(t)
+	id := fakeOPLRunID(t)
+	t.Setenv("FAKE_OPL_SLEEP", "60")
+	t.Setenv("FAKE_OPL_IGNORE_TILIM", "1")
+	p := cplexTestProblem(t)
+	p.SetSolverTimeout(1)
+
+	start := time.Now()
+	err := p.Solve()
+	elapsed := time.Since(start)
+	var timeoutErr *TimeoutError
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &timeoutErr) {
+		t.Fatalf("Solve() error = %v, want ErrTimeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Solve() error = %v, want deadline exceeded", err)
+	}
+	if elapsed > time.Second+solverTimeoutGrace+2*time.Second {
+		t.Errorf("hung OPL killed after %v", elapsed)
+	}
+	checkNoFakeOPLProcesses(t, id)
+}
+
+func TestCplexProblemCancel(t *testing.T) {
+	useFakeOPL(t)
+	id := fakeOPLRunID(t)
+	t.Setenv("FAKE_OPL_SLEEP", "60")
+	t.Setenv("FAKE_OPL_IGNORE_TILIM", "1")
+	p := cplexTestProblem(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := p.SolveContext(ctx)
+	elapsed := time.Since(start)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SolveContext() error = %v, want ErrTimeout by the context deadline", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("cancelled OPL killed after %v", elapsed)
+	}
+	checkNoFakeOPLProcesses(t, id)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	if err := p.SolveContext(ctx); !errors.Is(err, ErrTimeout) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("SolveContext() error = %v, want ErrTimeout by cancellation", err)
+	}
+	checkNoFakeOPLProcesses(t, id)
+}
+
+// small CPLEX problem, with its working directories in a temporary directory
+func cplexTestProblem(t *testing.T) *CplexProblem {
+	t.Helper()
+	p, err := CreateCplexProblem(config.MULTI, 2, 2, []float64{1, 2}, [][]int{{1, 1}, {1, 1}},
+		[][]float64{{10, 10}, {10, 10}}, []float64{5, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetBaseDir(t.TempDir())
+	return p
+}
+
+// mark processes of the fake oplrun, and their children, with an environment variable unique to the test
+func fakeOPLRunID(t *testing.T) string {
+	t.Helper()
+	if _, err := os.Stat("/proc/self/environ"); err != nil {
+		t.Skip("finding processes needs /proc")
+	}
+	id := "FAKE_OPL_TEST_ID=" + strconv.Itoa(os.Getpid()) + "-" + t.Name()
+	name, value, _ := strings.Cut(id, "=")
+	t.Setenv(name, value)
+	return id
+}
+
+// check that no process marked with id is left behind
+func checkNoFakeOPLProcesses(t *testing.T, id string) {
+	t.Helper()
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		environ, err := os.ReadFile(filepath.Join("/proc", e.Name(), "environ"))
+		if err != nil {
+			continue // exited, or not ours
+		}
+		for _, v := range strings.Split(string(environ), "\x00") {
+			if v == id {
+				cmdline, _ := os.ReadFile(filepath.Join("/proc", e.Name(), "cmdline"))
+				t.Errorf("process %d left behind: %s", pid, strings.ReplaceAll(string(cmdline), "\x00", " "))
+			}
+		}
+	}
+}
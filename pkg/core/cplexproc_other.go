@@ -0,0 +1,11 @@
+//go:build !unix
+
+package core
+
+import (
+	"os/exec"
+)
+
+// cancelling the command kills the process only
+func setKillProcessTree(cmd *exec.Cmd) {
+}
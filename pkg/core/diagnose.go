@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// Explanation of the infeasibility of a problem: a minimal set of servers and accelerator type
+// capacities (unitsAvail) that cannot be satisfied together, in the manner of an irreducible
+// infeasible subset (IIS), and the least additional units per type that restore feasibility
+type Infeasibility struct {
+	Servers []int // servers whose demand is in conflict
+	Types   []int // accelerator types whose capacity is in conflict
+
+	// least total number of additional units that restore feasibility, per type [numAcceleratorTypes];
+	// nil if no number of units can restore it, e.g. a server without accelerators with a positive rate,
+	// or if none is found within the solver timeout
+	AdditionalUnits []int
+
+	// false if some solves were inconclusive, e.g. timed out, so that the sets may not be minimal
+	Minimal bool
+}
+
+func (inf *Infeasibility) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "conflicting servers %v and capacities of types %v", inf.Servers, inf.Types)
+	if !inf.Minimal {
+		b.WriteString(" (may not be minimal)")
+	}
+	if inf.AdditionalUnits == nil {
+		b.WriteString("; no additional units restore feasibility")
+		return b.String()
+	}
+	var adds []string
+	for k, units := range inf.AdditionalUnits {
+		if units > 0 {
+			adds = append(adds, fmt.Sprintf("type %d: %d", k, units))
+		}
+	}
+	if len(adds) > 0 {
+		fmt.Fprintf(&b, "; additional units restoring feasibility: %s", strings.Join(adds, ", "))
+	}
+	return b.String()
+}
+
+// explain the infeasibility of the problem, solving variants of its model with the backend,
+// each within the solver timeout; returns nil if the problem is feasible, and a *TimeoutError
+// if the problem passes screening but its model is neither solved nor proved infeasible
+func (p *BaseProblem) ExplainInfeasibility(ctx context.Context) (*Infeasibility, error) {
+	if err := p.Setup(); err != nil {
+		return nil, err
+	}
+	d := &diagnosis{p: p, ctx: ctx}
+	all := make(map[int]bool, len(p.demandRows)+len(p.capRows))
+	for _, r := range append(append([]int{}, p.demandRows...), p.capRows...) {
+		all[r] = true
+	}
+
+	// a problem failing screening is infeasible, otherwise the solver decides
+	if screening := p.Screen(); screening.Feasible {
+		startTime := time.Now()
+		sol, err := d.solveRows(all)
+		if err != nil {
+			return nil, err
+		}
+		switch sol.Status {
+		case OPTIMAL, SUBOPTIMAL:
+			return nil, nil
+		case INFEASIBLE, NOFEASFOUND:
+		case TIMEOUT, USERABORT:
+			return nil, &TimeoutError{Elapsed: time.Since(startTime), Err: context.DeadlineExceeded, SolutionType: sol.Status}
+		default:
+			return nil, &SolveError{SolutionType: sol.Status, CplexStatus: sol.CplexStatus, SolverStatus: sol.SolverStatus}
+		}
+	}
+
+	// deletion filter: drop rows, capacities first, as long as the rest stays infeasible
+	inf := &Infeasibility{}
+	kept := all
+	for _, rows := range [][]int{p.capRows, p.demandRows} {
+		if err := d.filter(kept, rows); err != nil {
+			return nil, err
+		}
+	}
+	for i, r := range p.demandRows {
+		if kept[r] {
+			inf.Servers = append(inf.Servers, i)
+		}
+	}
+	for k, r := range p.capRows {
+		if kept[r] {
+			inf.Types = append(inf.Types, k)
+		}
+	}
+
+	additionalUnits, err := d.additionalUnits()
+	if err != nil {
+		return nil, err
+	}
+	inf.AdditionalUnits = additionalUnits
+	inf.Minimal = !d.inconclusive
+	return inf, nil
+}
+
+// state of an infeasibility diagnosis
+type diagnosis struct {
+	p            *BaseProblem
+	ctx          context.Context
+	inconclusive bool // some solve neither found a solution nor proved infeasibility
+}
+
+// remove rows from the kept set, as long as the kept set stays infeasible;
+// rows are tried in blocks, split in halves when removing a block makes the kept set feasible
+func (d *diagnosis) filter(kept map[int]bool, rows []int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, r := range rows {
+		delete(kept, r)
+	}
+	feasible, err := d.isFeasible(kept)
+	if err != nil {
+		return err
+	}
+	if !feasible {
+		return nil
+	}
+	for _, r := range rows {
+		kept[r] = true
+	}
+	if len(rows) == 1 {
+		return nil
+	}
+	if err := d.filter(kept, rows[:len(rows)/2]); err != nil {
+		return err
+	}
+	return d.filter(kept, rows[len(rows)/2:])
+}
+
+// check if the model is feasible with only the given demand and capacity rows;
+// inconclusive solves count as feasible, so that rows are kept
+func (d *diagnosis) isFeasible(kept map[int]bool) (bool, error) {
+	sol, err := d.solveRows(kept)
+	if err != nil {
+		return false, err
+	}
+	switch sol.Status {
+	case OPTIMAL, SUBOPTIMAL:
+		return true, nil
+	case INFEASIBLE, NOFEASFOUND:
+		return false, nil
+	}
+	d.inconclusive = true
+	return true, nil
+}
+
+// solve the model with only the given demand and capacity rows, and no objective
+func (d *diagnosis) solveRows(kept map[int]bool) (*Solution, error) {
+	m := d.p.model.Copy()
+	for k := range m.Objective {
+		m.Objective[k] = 0
+	}
+	conflictRows := make(map[int]bool, len(d.p.demandRows)+len(d.p.capRows))
+	for _, r := range append(append([]int{}, d.p.demandRows...), d.p.capRows...) {
+		conflictRows[r] = true
+	}
+	constraints := make([]model.Constraint, 0, len(m.Constraints))
+	for r, c := range m.Constraints {
+		if !conflictRows[r] || kept[r] {
+			constraints = append(constraints, c)
+		}
+	}
+	m.Constraints = constraints
+
+	return d.solve(m)
+}
+
+// least additional units per type restoring feasibility, adding an integer surplus variable to each capacity row;
+// nil if the problem stays infeasible, or no solution is found within the timeout
+func (d *diagnosis) additionalUnits() ([]int, error) {
+	m := d.p.model.Copy()
+	for k := range m.Objective {
+		m.Objective[k] = 0
+	}
+	surplus := make([]model.Var, len(d.p.capRows))
+	for k, r := range d.p.capRows {
+		surplus[k] = m.AddVar(fmt.Sprintf("surplus_type%d", k), model.Integer)
+		m.Objective[surplus[k]] = 1
+		m.Constraints[r].Row = append(m.Constraints[r].Row, model.Entry{Col: int(surplus[k]), Val: -1})
+	}
+
+	sol, err := d.solve(m)
+	if err != nil {
+		return nil, err
+	}
+	if sol.Status != OPTIMAL && sol.Status != SUBOPTIMAL {
+		return nil, nil
+	}
+	units := make([]int, len(surplus))
+	for k, v := range surplus {
+		units[k] = int(math.Round(sol.Values[v]))
+	}
+	return units, nil
+}
+
+// solve a variant of the model with the backend within the solver timeout
+func (d *diagnosis) solve(m *model.Model) (*Solution, error) {
+	sol, err := d.p.backend.Solve(d.ctx, m, d.p.solverTimeout())
+	if err != nil {
+		return nil, err
+	}
+	if d.ctx.Err() != nil {
+		return nil, &TimeoutError{Err: d.ctx.Err(), SolutionType: sol.Status}
+	}
+	return sol, nil
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// backend reporting the same solution type for every model
+type statusBackend struct {
+	status SolutionType
+}
+
+func (b *statusBackend) Name() string {
+	return "status"
+}
+
+func (b *statusBackend) Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error) {
+	return &Solution{Status: b.status}, nil
+}
+
+// two servers, each needing three replicas of a single accelerator, of one unit
+func createDiagnoseProblem(t *testing.T, unitsAvail int) *MultiAssignProblem {
+	t.Helper()
+	p, err := CreateMultiAssignProblem(2, 1, []float64{1}, [][]int{{1}, {1}}, [][]float64{{1}, {1}}, []float64{3, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetLimited(1, []int{unitsAvail}, [][]int{{1}}); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestExplainInfeasibility(t *testing.T) {
+	p := createDiagnoseProblem(t, 4)
+	p.SetBackend(NewGoBackend())
+	inf, err := p.ExplainInfeasibility(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Infeasibility{Servers: []int{0, 1}, Types: []int{0}, AdditionalUnits: []int{2}, Minimal: true}
+	if !reflect.DeepEqual(inf, want) {
+		t.Errorf("ExplainInfeasibility() = %+v, want %+v", inf, want)
+	}
+}
+
+func TestExplainInfeasibilityFeasible(t *testing.T) {
+	p := createDiagnoseProblem(t, 6)
+	p.SetBackend(NewGoBackend())
+	inf, err := p.ExplainInfeasibility(context.Background())
+	if inf != nil || err != nil {
+		t.Errorf("ExplainInfeasibility() = %v, %v, want nil, nil", inf, err)
+	}
+}
+
+func TestExplainInfeasibilityInconclusive(t *testing.T) {
+	// passing screening, the problem is undecided
+	p := createDiagnoseProblem(t, 6)
+	p.SetBackend(&statusBackend{status: TIMEOUT})
+	inf, err := p.ExplainInfeasibility(context.Background())
+	var timeoutErr *TimeoutError
+	if inf != nil || !errors.As(err, &timeoutErr) || !errors.Is(err, ErrTimeout) {
+		t.Fatalf("ExplainInfeasibility() = %v, %v, want *TimeoutError", inf, err)
+	}
+	if timeoutErr.SolutionType != TIMEOUT {
+		t.Errorf("solution type = %v, want %v", timeoutErr.SolutionType, TIMEOUT)
+	}
+
+	// failing screening, the problem is infeasible, though the explanation may not be minimal
+	p = createDiagnoseProblem(t, 4)
+	p.SetBackend(&statusBackend{status: TIMEOUT})
+	if p.Screen().Feasible {
+		t.Fatal("screening passed, want failed")
+	}
+	inf, err = p.ExplainInfeasibility(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inf == nil || inf.Minimal {
+		t.Errorf("ExplainInfeasibility() = %+v, want explanation that may not be minimal", inf)
+	}
+}
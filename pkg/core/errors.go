@@ -0,0 +1,164 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// outcomes of solving a problem, to be tested with errors.Is
+var (
+	ErrInfeasible = errors.New("problem is infeasible")
+	ErrUnbounded  = errors.New("problem is unbounded")
+	ErrTimeout    = errors.New("solve timed out")
+	ErrDimension  = errors.New("inconsistent dimension")
+	ErrValue      = errors.New("invalid value")
+	ErrBackend    = errors.New("solver backend failed")
+)
+
+// error returned when a solve is aborted because its context expired or was cancelled, discarding any incumbent solution;
+// matches ErrTimeout, as well as the context error
+type TimeoutError struct {
+	Elapsed      time.Duration // time spent solving before the abort
+	Err          error         // context error causing the abort
+	SolutionType SolutionType  // solution type reported by the solver
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("solve aborted after %v: %v", e.Elapsed, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// error returned when the solver finds no solution;
+// matches ErrInfeasible, ErrUnbounded, or otherwise ErrBackend, depending on the solution type
+type SolveError struct {
+	SolutionType SolutionType
+	CplexStatus  int    // CPLEX status code, zero if not solved by CPLEX
+	SolverStatus string // solver specific status, if any
+}
+
+func (e *SolveError) Error() string {
+	if e.SolverStatus != "" {
+		return fmt.Sprintf("LP solve failed; solutionType=%s; %s", e.SolutionType, e.SolverStatus)
+	}
+	return fmt.Sprintf("LP solve failed; solutionType=%s", e.SolutionType)
+}
+
+func (e *SolveError) Is(target error) bool {
+	switch e.SolutionType {
+	case INFEASIBLE, NOFEASFOUND:
+		return target == ErrInfeasible
+	case UNBOUNDED:
+		return target == ErrUnbounded
+	default:
+		return target == ErrBackend
+	}
+}
+
+// error returned when pre-solve screening finds a problem infeasible, without invoking the solver;
+// matches ErrInfeasible
+type ScreeningError struct {
+	Screening *Screening
+}
+
+func (e *ScreeningError) Error() string {
+	return fmt.Sprintf("%v (found before solving):\n%s", ErrInfeasible, e.Screening)
+}
+
+func (e *ScreeningError) Is(target error) bool {
+	return target == ErrInfeasible
+}
+
+// error in the dimension of a problem argument, e.g. a row of a matrix of the wrong length;
+// matches ErrDimension
+type DimensionError struct {
+	Arg   string // name of argument
+	Index int    // index of row in argument, -1 if the argument itself is wrong
+	Msg   string // what is wrong, e.g. length 3, expecting 4
+}
+
+func (e *DimensionError) Error() string {
+	if e.Index >= 0 {
+		return fmt.Sprintf("%v: %s[%d]: %s", ErrDimension, e.Arg, e.Index, e.Msg)
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrDimension, e.Arg, e.Msg)
+}
+
+func (e *DimensionError) Unwrap() error {
+	return ErrDimension
+}
+
+// error in the value of an element of a problem argument, e.g. a negative or NaN rate;
+// matches ErrValue
+type ValueError struct {
+	Arg     string  // name of argument
+	Indices []int   // indices of element in argument
+	Value   float64 // invalid value
+	Msg     string  // what is expected
+}
+
+func (e *ValueError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Arg)
+	for _, k := range e.Indices {
+		fmt.Fprintf(&b, "[%d]", k)
+	}
+	return fmt.Sprintf("%v: %s = %v: %s", ErrValue, b.String(), e.Value, e.Msg)
+}
+
+func (e *ValueError) Unwrap() error {
+	return ErrValue
+}
+
+// all invalid arguments of a problem, as *DimensionError and *ValueError;
+// matches ErrDimension and ErrValue, depending on the errors it holds
+type ValidationError struct {
+	Errs []error
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errs) == 1 {
+		return e.Errs[0].Error()
+	}
+	msgs := make([]string, len(e.Errs))
+	for k, err := range e.Errs {
+		msgs[k] = err.Error()
+	}
+	return fmt.Sprintf("%d invalid problem arguments: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+// error running a solver or reading its results, with the standard error output of the solver process, if any;
+// matches ErrBackend, as well as the underlying error
+type BackendError struct {
+	Backend string // name of backend
+	Stderr  string // standard error output of solver process
+	Err     error  // underlying error
+}
+
+func (e *BackendError) Error() string {
+	msg := fmt.Sprintf("%s backend: %v", e.Backend, e.Err)
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+func (e *BackendError) Unwrap() error {
+	return e.Err
+}
+
+func (e *BackendError) Is(target error) bool {
+	return target == ErrBackend
+}
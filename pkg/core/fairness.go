@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// MILP problem maximizing the least served fraction of the arrival rate of servers, optionally weighted,
+// with potential multiple kinds of accelerators assigned to a server, and then minimizing cost
+type FairnessProblem struct {
+	BaseProblem
+
+	weights   []float64 // weight of served fraction [numServers], one if nil
+	fairShare model.Var // least served fraction of servers, relative to their weights
+
+	resultFairShare float64 // resulting fair share
+}
+
+// create an instance of the problem
+func CreateFairnessProblem(numServers int, numAccelerators int, instanceCost []float64, numInstancesPerReplica [][]int,
+	ratePerReplica [][]float64, arrivalRates []float64) (*FairnessProblem, error) {
+	bp, err := CreateBaseProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+		ratePerReplica, arrivalRates)
+	if err != nil {
+		return nil, err
+	}
+	p := &FairnessProblem{
+		BaseProblem: *bp}
+	p.BaseProblem.Setup = p.Setup
+	p.BaseProblem.Screen = p.Screen
+	p.BaseProblem.Solve = p.Solve
+	p.BaseProblem.SolveContext = p.SolveContext
+	return p, nil
+}
+
+// set weights of servers [numServers]: a server is served at least the fair share times its weight,
+// as a fraction of its arrival rate; servers with a zero weight have no share
+func (p *FairnessProblem) SetWeights(weights []float64) error {
+	if err := validateWeights("weights", p.numServers, weights); err != nil {
+		return err
+	}
+	p.weights = weights
+	return nil
+}
+
+func (p *FairnessProblem) GetWeights() []float64 {
+	return p.weights
+}
+
+// weight of served fraction of server
+func (p *FairnessProblem) weight(i int) float64 {
+	if p.weights == nil {
+		return 1
+	}
+	return p.weights[i]
+}
+
+// largest fair share, at which all servers with a share are fully served
+func (p *FairnessProblem) maxFairShare() float64 {
+	maxShare := math.Inf(1)
+	for i := 0; i < p.numServers; i++ {
+		if p.arrivalRates[i] > 0 && p.weight(i) > 0 {
+			maxShare = math.Min(maxShare, 1/p.weight(i))
+		}
+	}
+	if math.IsInf(maxShare, 1) {
+		return 0
+	}
+	return maxShare
+}
+
+// setup constraints and objective function
+func (p *FairnessProblem) Setup() error {
+	// define LP problem: number of replicas for server and accelerator pairs, and fair share
+	p.model = model.NewModel(0)
+	p.addPairVars(model.Integer)
+	p.fairShare = p.model.AddBoundedVar("fair_share", model.Continuous, 0, p.maxFairShare())
+
+	// set objective function: fair share
+	if err := p.model.SetObjective(model.NewExpr().Add(1, p.fairShare)); err != nil {
+		return err
+	}
+	p.model.SetMaximize()
+
+	// set rate constraints: rate of replicas at least the weighted fair share of the arrival rate
+	p.demandRows = make([]int, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		rate := model.NewExpr()
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] != noVar {
+				rate.Add(p.targetRate(i, j), p.x[i][j])
+			}
+		}
+		rate.Add(-p.weight(i)*p.arrivalRates[i], p.fairShare)
+		p.demandRows[i] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("rate_server%d", i), rate, model.GE, 0); err != nil {
+			return err
+		}
+	}
+
+	// set replica bounds
+	if err := p.addReplicaBounds(); err != nil {
+		return err
+	}
+
+	// set count limit constraints
+	if err := p.addCapConstraints(func(i int, j int) float64 { return float64(p.numInstancesPerReplica[i][j]) }); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// check for evidence of infeasibility: as serving nothing is feasible, only of the least numbers of replicas of servers
+func (p *FairnessProblem) Screen() *Screening {
+	return p.screenMinReplicas()
+}
+
+// solve problem
+func (p *FairnessProblem) Solve() error {
+	return p.SolveContext(context.Background())
+}
+
+// solve problem, aborting the solver when the context is done:
+// maximize the fair share, then minimize cost while keeping the fair share; the objective value is the cost
+func (p *FairnessProblem) SolveContext(ctx context.Context) error {
+	// setup up problem
+	if err := p.Setup(); err != nil {
+		return err
+	}
+	p.resultFairShare = 0
+
+	// solve problem with timeout
+	if err := p.solveContext(ctx); err != nil {
+		return err
+	}
+	if err := p.solveCostSecondary(ctx, "fairness", model.NewExpr().Add(1, p.fairShare), p.replicaCost()); err != nil {
+		return err
+	}
+
+	// extract (optimal) solution
+	p.setResults(roundReplicas)
+	p.resultFairShare = p.maxFairShare()
+	for i := 0; i < p.numServers; i++ {
+		if p.arrivalRates[i] > 0 && p.weight(i) > 0 {
+			p.resultFairShare = math.Min(p.resultFairShare, p.servedRates[i]/(p.weight(i)*p.arrivalRates[i]))
+		}
+	}
+	return nil
+}
+
+// least served fraction of servers, relative to their weights
+func (p *FairnessProblem) GetFairShare() float64 {
+	return p.resultFairShare
+}
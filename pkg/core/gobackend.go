@@ -0,0 +1,265 @@
+package core
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+const (
+	integralityTol = 1e-6 // tolerance of integer variable values
+	pruneTol       = 1e-9 // relative tolerance when pruning nodes by bound
+)
+
+// Backend solving models in pure Go, using simplex and depth-first branch and bound;
+// intended for small to medium size models, and builds without cgo
+type GoBackend struct {
+	relax relaxationSolver // solver of LP relaxations, solveRelaxation if nil
+}
+
+// solver of the LP relaxation of a model with given variable bounds, returning its status, objective value, and values
+type relaxationSolver func(ctx context.Context, m *model.Model, cost []float64, lower []float64,
+	upper []float64) (SolutionType, float64, []float64)
+
+func NewGoBackend() *GoBackend {
+	return &GoBackend{}
+}
+
+func (b *GoBackend) Name() string {
+	return "go"
+}
+
+// branch and bound node: variable bounds and bound on objective value inherited from parent
+type bbNode struct {
+	lower []float64
+	upper []float64
+	bound float64
+}
+
+// solve model; when the timeout expires the best incumbent, if any, is returned (SUBOPTIMAL);
+// nodes whose relaxation fails, e.g. numerically, are dropped, and the incumbent, if any, is only SUBOPTIMAL
+// unless the bound of their parent shows that they hold no better solution
+func (b *GoBackend) Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error) {
+	solveCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	relax := b.relax
+	if relax == nil {
+		relax = solveRelaxation
+	}
+
+	// minimize, negating objective of maximization problems
+	sign := 1.0
+	if m.Maximize {
+		sign = -1.0
+	}
+	cost := make([]float64, m.NumVars())
+	for k, c := range m.Objective {
+		cost[k] = sign * c
+	}
+
+	root := &bbNode{
+		lower: make([]float64, m.NumVars()),
+		upper: make([]float64, m.NumVars()),
+		bound: math.Inf(-1),
+	}
+	for k, v := range m.Vars {
+		root.lower[k], root.upper[k] = v.Lower, v.Upper
+		if v.Kind != model.Continuous {
+			root.lower[k], root.upper[k] = math.Ceil(v.Lower-integralityTol), math.Floor(v.Upper+integralityTol)
+		}
+	}
+
+	var incumbent []float64
+	incumbentObj := math.Inf(1)
+	stack := []*bbNode{root}
+	var failed []*bbNode // dropped nodes whose relaxation failed
+	failedStatus := NUMFAILURE
+	for len(stack) > 0 {
+		if solveCtx.Err() != nil {
+			break
+		}
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node.bound >= incumbentObj-pruneTol*math.Max(1, math.Abs(incumbentObj)) {
+			continue
+		}
+
+		status, obj, x := relax(solveCtx, m, cost, node.lower, node.upper)
+		switch status {
+		case OPTIMAL:
+		case INFEASIBLE:
+			continue
+		case UNBOUNDED:
+			if node == root {
+				return &Solution{Status: UNBOUNDED}, nil
+			}
+			continue
+		case USERABORT:
+			// context done during relaxation, node stays open
+			stack = append(stack, node)
+			continue
+		default:
+			if node == root {
+				return &Solution{Status: status}, nil
+			}
+			failed = append(failed, node)
+			failedStatus = status
+			continue
+		}
+		if obj >= incumbentObj-pruneTol*math.Max(1, math.Abs(incumbentObj)) {
+			continue
+		}
+
+		// branch on most fractional integer variable
+		branch := -1
+		maxFrac := integralityTol
+		for k, v := range m.Vars {
+			if v.Kind == model.Continuous {
+				continue
+			}
+			if frac := math.Abs(x[k] - math.Round(x[k])); frac > maxFrac {
+				branch = k
+				maxFrac = frac
+			}
+		}
+		if branch < 0 {
+			incumbent = x
+			incumbentObj = obj
+			continue
+		}
+
+		// explore rounding up first, which tends to reach feasible assignments quickly
+		down := &bbNode{lower: node.lower, upper: cloneFloats(node.upper), bound: obj}
+		down.upper[branch] = math.Floor(x[branch])
+		up := &bbNode{lower: cloneFloats(node.lower), upper: node.upper, bound: obj}
+		up.lower[branch] = math.Ceil(x[branch])
+		stack = append(stack, down, up)
+	}
+
+	if incumbent == nil {
+		switch {
+		case ctx.Err() != nil:
+			return &Solution{Status: USERABORT}, nil
+		case solveCtx.Err() != nil:
+			return &Solution{Status: TIMEOUT}, nil
+		case len(failed) > 0:
+			return &Solution{Status: failedStatus}, nil
+		default:
+			return &Solution{Status: INFEASIBLE}, nil
+		}
+	}
+
+	// failed nodes that may hold a better solution than the incumbent stay open, with the bound of their parent
+	for _, node := range failed {
+		if node.bound < incumbentObj-pruneTol*math.Max(1, math.Abs(incumbentObj)) {
+			stack = append(stack, node)
+		}
+	}
+
+	sol := &Solution{Status: OPTIMAL, Objective: sign * incumbentObj, Values: incumbent, BestBound: sign * incumbentObj}
+	for k, v := range m.Vars {
+		if v.Kind != model.Continuous {
+			sol.Values[k] = math.Round(sol.Values[k])
+		}
+	}
+	if len(stack) > 0 {
+		// stopped early, or dropped failed nodes: bound is the least bound of open nodes
+		sol.Status = SUBOPTIMAL
+		bound := incumbentObj
+		for _, node := range stack {
+			bound = math.Min(bound, node.bound)
+		}
+		sol.BestBound = sign * bound
+	}
+	return sol, nil
+}
+
+// solve LP relaxation of model with given variable bounds
+func solveRelaxation(ctx context.Context, m *model.Model, cost []float64, lower []float64, upper []float64) (SolutionType, float64, []float64) {
+	n := m.NumVars()
+
+	// substitute variables by nonnegative ones: x = shift + scale*y[col] - y[negCol]
+	type varMap struct {
+		shift  float64
+		scale  float64
+		col    int
+		negCol int
+	}
+	vm := make([]varMap, n)
+	numCols := 0
+	var boundRows [][2]float64 // (column, upper bound) pairs
+	for k := 0; k < n; k++ {
+		lo, hi := lower[k], upper[k]
+		if lo > hi+integralityTol {
+			return INFEASIBLE, 0, nil
+		}
+		switch {
+		case !math.IsInf(lo, -1):
+			vm[k] = varMap{shift: lo, scale: 1, col: numCols, negCol: -1}
+			if !math.IsInf(hi, 1) {
+				boundRows = append(boundRows, [2]float64{float64(numCols), hi - lo})
+			}
+		case !math.IsInf(hi, 1):
+			vm[k] = varMap{shift: hi, scale: -1, col: numCols, negCol: -1}
+		default:
+			vm[k] = varMap{shift: 0, scale: 1, col: numCols, negCol: numCols + 1}
+			numCols++
+		}
+		numCols++
+	}
+
+	lp := &standardLP{c: make([]float64, numCols)}
+	offset := 0.0
+	for k, c := range cost {
+		if c == 0 {
+			continue
+		}
+		offset += c * vm[k].shift
+		lp.c[vm[k].col] += c * vm[k].scale
+		if vm[k].negCol >= 0 {
+			lp.c[vm[k].negCol] -= c
+		}
+	}
+	for _, c := range m.Constraints {
+		row := make([]float64, numCols)
+		rhs := c.RHS
+		for _, e := range c.Row {
+			rhs -= e.Val * vm[e.Col].shift
+			row[vm[e.Col].col] += e.Val * vm[e.Col].scale
+			if vm[e.Col].negCol >= 0 {
+				row[vm[e.Col].negCol] -= e.Val
+			}
+		}
+		lp.a = append(lp.a, row)
+		lp.ops = append(lp.ops, c.Type)
+		lp.b = append(lp.b, rhs)
+	}
+	for _, br := range boundRows {
+		row := make([]float64, numCols)
+		row[int(br[0])] = 1
+		lp.a = append(lp.a, row)
+		lp.ops = append(lp.ops, model.LE)
+		lp.b = append(lp.b, br[1])
+	}
+
+	status, obj, y := lp.solve(ctx)
+	if status != OPTIMAL {
+		return status, 0, nil
+	}
+	x := make([]float64, n)
+	for k := 0; k < n; k++ {
+		x[k] = vm[k].shift + vm[k].scale*y[vm[k].col]
+		if vm[k].negCol >= 0 {
+			x[k] -= y[vm[k].negCol]
+		}
+	}
+	return OPTIMAL, obj + offset, x
+}
+
+func cloneFloats(x []float64) []float64 {
+	y := make([]float64, len(x))
+	copy(y, x)
+	return y
+}
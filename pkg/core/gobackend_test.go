@@ -0,0 +1,251 @@
+package core
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/lpformat"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// model with the given objective, direction, and variables
+func testModel(maximize bool, objective []float64, vars ...model.Variable) *model.Model {
+	return &model.Model{Vars: vars, Objective: objective, Maximize: maximize}
+}
+
+func continuous(lower float64, upper float64) model.Variable {
+	return model.Variable{Kind: model.Continuous, Lower: lower, Upper: upper}
+}
+
+func integer(lower float64, upper float64) model.Variable {
+	return model.Variable{Kind: model.Integer, Lower: lower, Upper: upper}
+}
+
+func TestGoBackendSolve(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name   string
+		model  func() *model.Model
+		status SolutionType
+		obj    float64
+		values []float64 // expected values, not checked if nil
+	}{
+		{
+			name: "LP fixture",
+			model: func() *model.Model {
+				m, err := lpformat.ParseFile("../../demos/cmd/test-lp.lp")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return m
+			},
+			status: OPTIMAL, obj: 6315.625, values: []float64{21.875, 53.125},
+		},
+		{
+			name: "MILP fixture",
+			model: func() *model.Model {
+				m, err := lpformat.ParseFile("../../demos/cmd/test-mip.lp")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return m
+			},
+			// by enumeration of all integer points
+			status: OPTIMAL, obj: 6266, values: []float64{22, 52},
+		},
+		{
+			name: "free variable",
+			model: func() *model.Model {
+				m := testModel(false, []float64{1}, continuous(math.Inf(-1), inf))
+				m.AddConstraintSparse([]model.Entry{{Col: 0, Val: 1}}, model.GE, -5)
+				return m
+			},
+			status: OPTIMAL, obj: -5, values: []float64{-5},
+		},
+		{
+			name: "upper bounded only",
+			model: func() *model.Model {
+				return testModel(true, []float64{1}, continuous(math.Inf(-1), 3))
+			},
+			status: OPTIMAL, obj: 3, values: []float64{3},
+		},
+		{
+			name: "knapsack",
+			model: func() *model.Model {
+				m := testModel(true, []float64{10, 13, 7, 8},
+					integer(0, 1), integer(0, 1), integer(0, 1), integer(0, 1))
+				m.AddConstraint([]float64{5, 7, 4, 3}, model.LE, 14)
+				return m
+			},
+			status: OPTIMAL, obj: 28, values: []float64{0, 1, 1, 1},
+		},
+		{
+			name: "integer infeasible",
+			model: func() *model.Model {
+				m := testModel(false, []float64{1}, integer(0, inf))
+				m.AddConstraint([]float64{2}, model.EQ, 1)
+				return m
+			},
+			status: INFEASIBLE,
+		},
+		{
+			name: "infeasible bounds",
+			model: func() *model.Model {
+				m := testModel(false, []float64{1}, continuous(0, 1))
+				m.AddConstraint([]float64{1}, model.GE, 2)
+				return m
+			},
+			status: INFEASIBLE,
+		},
+		{
+			name: "unbounded",
+			model: func() *model.Model {
+				m := testModel(true, []float64{1, 1}, integer(0, inf), continuous(0, inf))
+				m.AddConstraint([]float64{1, -1}, model.LE, 1)
+				return m
+			},
+			status: UNBOUNDED,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sol, err := NewGoBackend().Solve(context.Background(), tt.model(), 10*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sol.Status != tt.status {
+				t.Fatalf("status = %v, want %v", sol.Status, tt.status)
+			}
+			if sol.Status != OPTIMAL {
+				return
+			}
+			if math.Abs(sol.Objective-tt.obj) > 1e-6 {
+				t.Errorf("objective = %v, want %v", sol.Objective, tt.obj)
+			}
+			if sol.BestBound != sol.Objective {
+				t.Errorf("best bound = %v, want objective %v", sol.BestBound, sol.Objective)
+			}
+			for k, want := range tt.values {
+				if math.Abs(sol.Values[k]-want) > 1e-6 {
+					t.Errorf("value[%d] = %v, want %v", k, sol.Values[k], want)
+				}
+			}
+		})
+	}
+}
+
+// hard MILP for branch and bound: max sum 2 x_k subject to sum 2 x_k <= n, for odd n;
+// an incumbent of value n-1 is found quickly, but proving it optimal needs exponentially many nodes
+func TestGoBackendSuboptimal(t *testing.T) {
+	const n = 41
+	m := model.NewModel(0)
+	sum := model.NewExpr()
+	for k := 0; k < n; k++ {
+		sum.Add(2, m.AddVar("", model.Binary))
+	}
+	if err := m.SetObjective(sum); err != nil {
+		t.Fatal(err)
+	}
+	m.SetMaximize()
+	if err := m.AddConstr("", sum, model.LE, n); err != nil {
+		t.Fatal(err)
+	}
+
+	sol, err := NewGoBackend().Solve(context.Background(), m, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Status != SUBOPTIMAL {
+		t.Fatalf("status = %v, want %v", sol.Status, SUBOPTIMAL)
+	}
+	if sol.Objective != n-1 {
+		t.Errorf("objective = %v, want %v", sol.Objective, n-1)
+	}
+	// the bound of open nodes is at most the LP relaxation bound, and at least the incumbent
+	if sol.BestBound < sol.Objective || sol.BestBound > n+1e-6 {
+		t.Errorf("best bound = %v, want in [%v, %v]", sol.BestBound, sol.Objective, n)
+	}
+}
+
+func TestGoBackendCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m := testModel(true, []float64{1}, integer(0, 3))
+	sol, err := NewGoBackend().Solve(ctx, m, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Status != USERABORT {
+		t.Errorf("status = %v, want %v", sol.Status, USERABORT)
+	}
+}
+
+// nodes whose relaxation fails are not silently dropped from the search
+func TestGoBackendFailedNodes(t *testing.T) {
+	// knapsack, with LP relaxation bound 29+1/7 at the root, branching on x1, and optimum 28 with x1 = 1
+	knapsack := func() *model.Model {
+		m := testModel(true, []float64{10, 13, 7, 8}, integer(0, 1), integer(0, 1), integer(0, 1), integer(0, 1))
+		m.AddConstraint([]float64{5, 7, 4, 3}, model.LE, 14)
+		return m
+	}
+	tests := []struct {
+		name      string
+		fails     func(lower []float64, upper []float64) bool // nodes whose relaxation fails
+		status    SolutionType
+		obj       float64
+		bestBound float64
+	}{
+		{
+			name:   "subtree with optimum",
+			fails:  func(lower []float64, upper []float64) bool { return lower[1] == 1 },
+			status: SUBOPTIMAL, obj: 25, bestBound: 29 + 1.0/7,
+		},
+		{
+			// branching on x0 with x1 = 1, with bound 29
+			name:   "subtree without optimum",
+			fails:  func(lower []float64, upper []float64) bool { return lower[1] == 1 && lower[0] == 1 },
+			status: SUBOPTIMAL, obj: 28, bestBound: 29,
+		},
+		{
+			name: "all but root",
+			fails: func(lower []float64, upper []float64) bool {
+				for k := range lower {
+					if lower[k] > 0 || upper[k] < 1 {
+						return true
+					}
+				}
+				return false
+			},
+			status: NUMFAILURE,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &GoBackend{relax: func(ctx context.Context, m *model.Model, cost []float64, lower []float64,
+				upper []float64) (SolutionType, float64, []float64) {
+				if tt.fails(lower, upper) {
+					return NUMFAILURE, 0, nil
+				}
+				return solveRelaxation(ctx, m, cost, lower, upper)
+			}}
+			sol, err := b.Solve(context.Background(), knapsack(), 10*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sol.Status != tt.status {
+				t.Fatalf("status = %v, want %v", sol.Status, tt.status)
+			}
+			if sol.Status != OPTIMAL && sol.Status != SUBOPTIMAL {
+				return
+			}
+			if math.Abs(sol.Objective-tt.obj) > 1e-6 {
+				t.Errorf("objective = %v, want %v", sol.Objective, tt.obj)
+			}
+			if math.Abs(sol.BestBound-tt.bestBound) > 1e-6 {
+				t.Errorf("best bound = %v, want %v", sol.BestBound, tt.bestBound)
+			}
+		})
+	}
+}
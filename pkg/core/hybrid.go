@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// MILP problem with at most a number of kinds of accelerators assigned to a server:
+// binary selection of server and accelerator pairs, linked to their integer number of replicas
+type HybridAssignProblem struct {
+	BaseProblem
+
+	maxKinds       int   // most number of accelerator kinds of a server
+	serverMaxKinds []int // most number of accelerator kinds of servers [numServers], maxKinds if nil or negative
+}
+
+// create an instance of the problem
+func CreateHybridAssignProblem(numServers int, numAccelerators int, instanceCost []float64, numInstancesPerReplica [][]int,
+	ratePerReplica [][]float64, arrivalRates []float64) (*HybridAssignProblem, error) {
+	bp, err := CreateBaseProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+		ratePerReplica, arrivalRates)
+	if err != nil {
+		return nil, err
+	}
+	p := &HybridAssignProblem{
+		BaseProblem: *bp,
+		maxKinds:    config.DefaultMaxKinds}
+	p.BaseProblem.Setup = p.Setup
+	p.BaseProblem.Screen = p.Screen
+	p.BaseProblem.Solve = p.Solve
+	p.BaseProblem.SolveContext = p.SolveContext
+	return p, nil
+}
+
+// set most number of accelerator kinds of a server
+func (p *HybridAssignProblem) SetMaxKinds(maxKinds int) error {
+	if maxKinds <= 0 {
+		return &ValueError{Arg: "maxKinds", Value: float64(maxKinds), Msg: "expecting a positive number"}
+	}
+	p.maxKinds = maxKinds
+	return nil
+}
+
+func (p *HybridAssignProblem) GetMaxKinds() int {
+	return p.maxKinds
+}
+
+// set most number of accelerator kinds of servers [numServers], overriding the most number of a server;
+// negative for no override, nil for none
+func (p *HybridAssignProblem) SetServerMaxKinds(serverMaxKinds []int) error {
+	if serverMaxKinds != nil {
+		if err := validateMaxKinds(p.numServers, serverMaxKinds); err != nil {
+			return err
+		}
+	}
+	p.serverMaxKinds = serverMaxKinds
+	return nil
+}
+
+func (p *HybridAssignProblem) GetServerMaxKinds() []int {
+	return p.serverMaxKinds
+}
+
+// most number of accelerator kinds of server
+func (p *HybridAssignProblem) maxKindsOfServer(i int) int {
+	if p.serverMaxKinds == nil || p.serverMaxKinds[i] < 0 {
+		return p.maxKinds
+	}
+	return p.serverMaxKinds[i]
+}
+
+// setup constraints and objective function
+func (p *HybridAssignProblem) Setup() error {
+	// define LP problem: number of replicas and selection of server and accelerator pairs
+	p.model = model.NewModel(0)
+	p.addPairVars(model.Integer)
+	p.addSelectionVars()
+
+	// set objective function: cost coefficients
+	if err := p.model.SetObjective(p.replicaCost()); err != nil {
+		return err
+	}
+
+	// set rate constraints: rate coefficients
+	p.demandRows = make([]int, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		rate := model.NewExpr()
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] != noVar {
+				rate.Add(p.targetRate(i, j), p.x[i][j])
+			}
+		}
+		p.demandRows[i] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("rate_server%d", i), rate, model.GE, p.arrivalRates[i]); err != nil {
+			return err
+		}
+	}
+
+	// set link constraints: replicas only of selected pairs, and kind constraints: selected pairs of servers
+	if err := p.addLinkConstraints(); err != nil {
+		return err
+	}
+	for i := 0; i < p.numServers; i++ {
+		if err := p.model.AddConstr(fmt.Sprintf("kinds_server%d", i), p.selection(i), model.LE, float64(p.maxKindsOfServer(i))); err != nil {
+			return err
+		}
+	}
+
+	// set replica bounds
+	if err := p.addReplicaBounds(); err != nil {
+		return err
+	}
+
+	// set count limit constraints
+	if err := p.addCapConstraints(func(i int, j int) float64 { return float64(p.numInstancesPerReplica[i][j]) }); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// check for evidence of infeasibility, cheaply and without invoking the solver
+func (p *HybridAssignProblem) Screen() *Screening {
+	return p.screen(false,
+		func(i int, j int) float64 { return 1 },
+		func(i int, j int) float64 { return p.arrivalRates[i] / p.targetRate(i, j) })
+}
+
+// solve problem
+func (p *HybridAssignProblem) Solve() error {
+	return p.SolveContext(context.Background())
+}
+
+// solve problem, aborting the solver when the context is done
+func (p *HybridAssignProblem) SolveContext(ctx context.Context) error {
+	// setup up problem
+	if err := p.Setup(); err != nil {
+		return err
+	}
+
+	// solve problem with timeout
+	if err := p.solveContext(ctx); err != nil {
+		return err
+	}
+
+	// extract (optimal) solution
+	p.setResults(roundReplicas)
+	return nil
+}
@@ -1,7 +1,8 @@
 package core
 
 import (
-	"github.com/draffensperger/golp"
+	"context"
+	"io"
 )
 
 // interface to an optimization problem
@@ -11,18 +12,41 @@ type Problem interface {
 	UnSetLimited()
 	IsLimited() bool
 
+	// bounding number of replicas
+	SetReplicaBounds(minReplicas []int, maxReplicas []int, maxPairReplicas [][]int) error
+	UnSetReplicaBounds()
+
+	// leaving headroom in rate of replicas
+	SetTargetUtilization(targetUtilization []float64) error
+	UnSetTargetUtilization()
+
 	// pre-solve setup
 	Setup() error
+	// pre-solve feasibility screening, without invoking the solver
+	Screen() *Screening
+	// export model
+	WriteLP(w io.Writer) error
+	WriteMPS(w io.Writer) error
 	// solve problem
+	SetBackend(Backend)
+	GetBackend() Backend
 	SetSolverTimeout(int)
 	GetSolverTimeout() int
 	Solve() error
+	SolveContext(ctx context.Context) error
+	// explain infeasibility of problem, nil if feasible, a *TimeoutError if undecided within the solver timeout
+	ExplainInfeasibility(ctx context.Context) (*Infeasibility, error)
 
 	// problem solution
-	GetSolutionType() golp.SolutionType
+	GetSolutionType() SolutionType
 	GetSolutionTimeMsec() int64
 	GetObjectiveValue() float64
+	GetBestBound() float64
+	GetMIPGap() float64
 	GetNumReplicas() [][]int
 	GetInstancesUsed() []int
 	GetUnitsUsed() []int
+	GetServedRates() []float64
+	GetDroppedRates() []float64
+	GetUtilization() []float64
 }
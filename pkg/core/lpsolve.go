@@ -0,0 +1,244 @@
+//go:build cgo && !purego
+
+package core
+
+/*
+#cgo darwin CFLAGS: -I/opt/local/include/lpsolve
+#cgo darwin LDFLAGS: -L/opt/local/lib -llpsolve55
+#cgo linux CFLAGS: -I/usr/include/lpsolve
+#cgo linux LDFLAGS: -llpsolve55
+
+#include "lp_lib.h"
+#include <stdlib.h>
+
+// abort callback polled by lp_solve: a non-zero flag stops the solve
+static int abort_on_flag(lprec *lp, void *userhandle) {
+	return __atomic_load_n((int *) userhandle, __ATOMIC_SEQ_CST);
+}
+
+static void set_abort_flag(lprec *lp, int *flag) {
+	put_abortfunc(lp, abort_on_flag, flag);
+}
+
+static void clear_abort_flag(lprec *lp) {
+	put_abortfunc(lp, NULL, NULL);
+}
+
+static void raise_abort_flag(int *flag) {
+	__atomic_store_n(flag, 1, __ATOMIC_SEQ_CST);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/draffensperger/golp"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+func init() {
+	backendConstructors["lp_solve"] = func() Backend { return NewLPSolveBackend() }
+}
+
+// Backend solving models with lp_solve
+type LPSolveBackend struct{}
+
+func NewLPSolveBackend() *LPSolveBackend {
+	return &LPSolveBackend{}
+}
+
+func (b *LPSolveBackend) Name() string {
+	return "lp_solve"
+}
+
+// solve model with lp_solve; when the timeout expires the best incumbent, if any, is returned (SUBOPTIMAL),
+// with the bound of the root LP relaxation, which may take up to the timeout grace period more
+func (b *LPSolveBackend) Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error) {
+	if errGolpLayout != nil {
+		return nil, &BackendError{Backend: b.Name(), Err: errGolpLayout}
+	}
+	lp, err := newLP(m)
+	if err != nil {
+		return nil, &BackendError{Backend: b.Name(), Err: err}
+	}
+
+	// lp_solve stops by itself at the timeout, the context deadline only guards against overrun
+	setLPTimeout(lp, timeout)
+	solveCtx, cancel := context.WithTimeout(ctx, timeout+solverTimeoutGrace)
+	defer cancel()
+
+	// lp.SetVerboseLevel(golp.DETAILED)
+	sol := &Solution{Status: SolutionType(solveLP(solveCtx, lp))}
+	if sol.Status != OPTIMAL && sol.Status != SUBOPTIMAL {
+		return sol, nil
+	}
+	sol.Objective = lp.Objective()
+	sol.Values = lp.Variables()
+
+	// bound on objective value: exact if optimal, root LP relaxation otherwise
+	sol.BestBound = sol.Objective
+	if sol.Status == SUBOPTIMAL {
+		if bound, ok := relaxationBound(ctx, lp, solverTimeoutGrace); ok {
+			sol.BestBound = bound
+		}
+	}
+	return sol, nil
+}
+
+// create lp_solve problem from model, adding constraints by their nonzero coefficients
+func newLP(m *model.Model) (*golp.LP, error) {
+	if m.NumVars() == 0 {
+		// golp needs at least one column
+		return nil, errors.New("model has no variables")
+	}
+	lp := golp.NewLP(0, m.NumVars()) // in row entry mode until the objective function is set
+
+	for r, c := range m.Constraints {
+		row := make([]golp.Entry, len(c.Row), len(c.Row)+1)
+		for k, e := range c.Row {
+			row[k] = golp.Entry{Col: e.Col, Val: e.Val}
+		}
+		if len(row) == 0 {
+			// golp needs at least one entry
+			row = append(row, golp.Entry{Col: 0, Val: 0})
+		}
+		// golp ignores the result of lp_solve, which adds no row on failure
+		if err := lp.AddConstraintSparse(row, lpConstraintType(c.Type), c.RHS); err != nil {
+			return nil, fmt.Errorf("constraint %s: %w", m.ConstraintName(r), err)
+		}
+		if lp.NumRows() != r+1 {
+			return nil, fmt.Errorf("constraint %s: not added by lp_solve", m.ConstraintName(r))
+		}
+	}
+
+	lp.SetObjFn(m.Objective)
+	if m.Maximize {
+		lp.SetMaximize()
+	}
+
+	for k, v := range m.Vars {
+		lp.SetColName(k, m.VarName(k))
+		switch v.Kind {
+		case model.Integer:
+			lp.SetInt(k, true)
+		case model.Binary:
+			lp.SetBinary(k, true)
+		}
+		if v.Lower != 0 || !math.IsInf(v.Upper, 1) {
+			setLPBounds(lp, k, v.Lower, v.Upper)
+		}
+	}
+	for r := range m.Constraints {
+		setLPRowName(lp, r, m.ConstraintName(r))
+	}
+	return lp, nil
+}
+
+func lpConstraintType(ct model.ConstraintType) golp.ConstraintType {
+	switch ct {
+	case model.LE:
+		return golp.LE
+	case model.GE:
+		return golp.GE
+	default:
+		return golp.EQ
+	}
+}
+
+// underlying lp_solve model of a golp LP, for the calls golp lacks (abort function, bounds, row names, timeout);
+// golp does not export it, but LP holds the *lprec as its only field in the golp version pinned in go.mod
+// (v0.0.0-20241201023928-94a60bf898d2), which errGolpLayout checks before any solve
+func lprecOf(lp *golp.LP) *C.lprec {
+	return *(**C.lprec)(unsafe.Pointer(lp))
+}
+
+// error if the layout of golp.LP is not the one lprecOf assumes, e.g. after a golp upgrade
+var errGolpLayout = checkGolpLayout()
+
+func checkGolpLayout() error {
+	t := reflect.TypeOf(golp.LP{})
+	lprec := reflect.TypeOf((*C.lprec)(nil))
+	if t.NumField() != 1 || t.Field(0).Offset != 0 || t.Field(0).Type.Kind() != reflect.Pointer ||
+		t.Field(0).Type.Elem().Name() != lprec.Elem().Name() {
+		return errors.New("unsupported golp version: golp.LP does not hold only a *lprec")
+	}
+	return nil
+}
+
+// solve LP, aborting lp_solve through its abort callback when the context is done;
+// the solve has always returned by the time this function does
+func solveLP(ctx context.Context, lp *golp.LP) golp.SolutionType {
+	// the flag lives in C memory since lp_solve keeps a reference to it during the solve
+	flag := (*C.int)(C.calloc(1, C.sizeof_int))
+	defer C.free(unsafe.Pointer(flag))
+
+	lprec := lprecOf(lp)
+	C.set_abort_flag(lprec, flag)
+	defer C.clear_abort_flag(lprec)
+	defer runtime.KeepAlive(lp)
+
+	done := make(chan golp.SolutionType, 1)
+	go func() {
+		done <- lp.Solve()
+	}()
+
+	select {
+	case solutionType := <-done:
+		return solutionType
+	case <-ctx.Done():
+		C.raise_abort_flag(flag)
+		return <-done
+	}
+}
+
+// set bounds of a variable (golp only supports making variables unbounded)
+func setLPBounds(lp *golp.LP, col int, lower float64, upper float64) {
+	inf := C.get_infinite(lprecOf(lp))
+	if math.IsInf(lower, -1) {
+		lower = -float64(inf)
+	}
+	if math.IsInf(upper, 1) {
+		upper = float64(inf)
+	}
+	C.set_bounds(lprecOf(lp), C.int(col+1), C.REAL(lower), C.REAL(upper))
+}
+
+// set name of a constraint (golp only supports naming columns)
+func setLPRowName(lp *golp.LP, row int, name string) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	C.set_row_name(lprecOf(lp), C.int(row+1), cname)
+}
+
+// set the lp_solve time limit, after which a MILP solve stops with its best incumbent (SUBOPTIMAL),
+// or with TIMEOUT if none was found
+func setLPTimeout(lp *golp.LP, timeout time.Duration) {
+	sec := int64(math.Ceil(timeout.Seconds()))
+	C.set_timeout(lprecOf(lp), C.long(sec))
+}
+
+// objective value of the root LP relaxation of a MILP, solved within a timeout: a bound on its optimal objective value;
+// lp_solve does not report the bound of its branch and bound, so the bound is weaker, and the gap larger, than the solver's own
+func relaxationBound(ctx context.Context, lp *golp.LP, timeout time.Duration) (float64, bool) {
+	relaxed := lp.Copy()
+	for k := 0; k < relaxed.NumCols(); k++ {
+		// binary variables keep their [0,1] bounds
+		relaxed.SetInt(k, false)
+	}
+	setLPTimeout(relaxed, timeout)
+	relaxCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	solutionType := solveLP(relaxCtx, relaxed)
+	if solutionType != golp.OPTIMAL {
+		return 0, false
+	}
+	return relaxed.Objective(), true
+}
@@ -0,0 +1,57 @@
+//go:build cgo && !purego
+
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/config"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+func TestNewLP(t *testing.T) {
+	m := model.NewModel(0)
+	x := m.AddVar("x", model.Integer)
+	m.AddConstr("c1", model.NewExpr().Add(1, x), model.LE, 4)
+	m.AddConstr("empty", model.NewExpr(), model.GE, 0)
+	lp, err := newLP(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lp.NumRows() != 2 {
+		t.Errorf("rows = %d, want 2", lp.NumRows())
+	}
+}
+
+// a model without variables is an error, rather than a panic in golp
+func TestLPSolveBackendEmptyModel(t *testing.T) {
+	m := model.NewModel(0)
+	m.AddConstr("empty", model.NewExpr(), model.LE, 1)
+	_, err := NewLPSolveBackend().Solve(context.Background(), m, time.Second)
+	var backendErr *BackendError
+	if !errors.As(err, &backendErr) {
+		t.Fatalf("Solve() error = %v, want *BackendError", err)
+	}
+}
+
+// setup of the model, and of the lp_solve model built from it with sparse rows
+func BenchmarkSetupLPSolve(b *testing.B) {
+	for _, problemType := range []config.ProblemType{config.SINGLE, config.MULTI} {
+		b.Run(problemType.String(), func(b *testing.B) {
+			p, base := createBenchProblem(b, problemType, benchServers, benchAccelerators)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if err := p.Setup(); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := newLP(base.model); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,14 +1,20 @@
 package core
 
 import (
+	"context"
+	"fmt"
 	"math"
 
-	"github.com/draffensperger/golp"
+	"github.com/llm-inferno/lpsolve/pkg/model"
 )
 
 // MILP problem with potential multiple kinds of accelerators assigned to a server
 type MultiAssignProblem struct {
 	BaseProblem
+
+	isSoftDemand    bool        // arrival rates may be partly unserved, at a penalty
+	unservedPenalty []float64   // penalty per unit of unserved arrival rate [numServers]
+	unserved        []model.Var // unserved arrival rate variables [numServers], if soft demand
 }
 
 // create an instance of the problem
@@ -22,106 +28,126 @@ func CreateMultiAssignProblem(numServers int, numAccelerators int, instanceCost
 	p := &MultiAssignProblem{
 		BaseProblem: *bp}
 	p.BaseProblem.Setup = p.Setup
+	p.BaseProblem.Screen = p.Screen
 	p.BaseProblem.Solve = p.Solve
+	p.BaseProblem.SolveContext = p.SolveContext
 	return p, nil
 }
 
 // setup constraints and objective function
 func (p *MultiAssignProblem) Setup() error {
-	// define LP problem
-	numVars := p.numServers * p.numAccelerators
-	p.lp = golp.NewLP(0, numVars)
-	for k := 0; k < numVars; k++ {
-		p.lp.SetInt(k, true)
-	}
+	// define LP problem: number of replicas for server and accelerator pairs
+	p.model = model.NewModel(0)
+	p.addPairVars(model.Integer)
 
 	// set objective function: cost coefficients
-	costVector := make([]float64, numVars)
-	for i := 0; i < p.numServers; i++ {
-		v0 := i * p.numAccelerators // begin index
-		for j := 0; j < p.numAccelerators; j++ {
-			costVector[v0+j] = float64(p.numInstancesPerReplica[i][j]) * p.instanceCost[j]
+	cost := p.replicaCost()
+
+	// unserved arrival rate variables, penalized in the objective
+	p.unserved = nil
+	if p.isSoftDemand {
+		p.unserved = make([]model.Var, p.numServers)
+		for i := 0; i < p.numServers; i++ {
+			p.unserved[i] = p.model.AddBoundedVar(fmt.Sprintf("unserved_server%d", i), model.Continuous, 0, p.arrivalRates[i])
+			cost.Add(p.unservedPenalty[i], p.unserved[i])
 		}
 	}
-	p.lp.SetObjFn(costVector)
-	// fmt.Println(utils.Pretty1D("costVector", costVector))
-
-	// excluded infeasible variables (for a given server accelerator pair)
-	excluded := make([]float64, numVars)
+	if err := p.model.SetObjective(cost); err != nil {
+		return err
+	}
 
-	// set rate constraints: rate coefficients
+	// set rate constraints: rate coefficients, and unserved rate if soft demand
+	p.demandRows = make([]int, p.numServers)
 	for i := 0; i < p.numServers; i++ {
-		rateVector := make([]float64, numVars)
-		v0 := i * p.numAccelerators // begin index
+		rate := model.NewExpr()
 		for j := 0; j < p.numAccelerators; j++ {
-			rateVector[v0+j] = p.ratePerReplica[i][j]
-			if p.ratePerReplica[i][j] == 0 {
-				excluded[v0+j] = 1
+			if p.x[i][j] != noVar {
+				rate.Add(p.targetRate(i, j), p.x[i][j])
 			}
 		}
-		p.lp.AddConstraint(rateVector, golp.GE, p.arrivalRates[i])
-		// fmt.Printf("i=%d; %s arrv=%v\n", i, utils.Pretty1D("rateVector", rateVector), p.arrivalRates[i])
+		if p.isSoftDemand {
+			rate.Add(1, p.unserved[i])
+		}
+		p.demandRows[i] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("rate_server%d", i), rate, model.GE, p.arrivalRates[i]); err != nil {
+			return err
+		}
+	}
+
+	// set replica bounds
+	if err := p.addReplicaBounds(); err != nil {
+		return err
 	}
 
 	// set count limit constraints
-	if p.isLimited {
-		for k := 0; k < p.numAcceleratorTypes; k++ {
-			countVector := make([]float64, numVars)
-			for i := 0; i < p.numServers; i++ {
-				for j := 0; j < p.numAccelerators; j++ {
-					if p.acceleratorTypesMatrix[k][j] > 0 {
-						idx := i*p.numAccelerators + j
-						countVector[idx] = float64(p.numInstancesPerReplica[i][j] * p.acceleratorTypesMatrix[k][j])
-					}
-				}
-			}
-			p.lp.AddConstraint(countVector, golp.LE, float64(p.unitsAvail[k]))
-			// fmt.Printf("k=%d; %s avail=%d\n", k, utils.Pretty1D("countVector", countVector), p.unitsAvail[k])
-		}
+	if err := p.addCapConstraints(func(i int, j int) float64 { return float64(p.numInstancesPerReplica[i][j]) }); err != nil {
+		return err
 	}
 
-	p.lp.AddConstraint(excluded, golp.EQ, 0)
-	// fmt.Println(utils.Pretty1D("excluded", excluded))
+	return nil
+}
 
+// set soft demand option: arrival rates may be partly unserved, at a penalty per unit of unserved rate [numServers]
+func (p *MultiAssignProblem) SetSoftDemand(unservedPenalty []float64) error {
+	if err := validateSoftDemand(p.numServers, unservedPenalty); err != nil {
+		return err
+	}
+	p.isSoftDemand = true
+	p.unservedPenalty = unservedPenalty
 	return nil
 }
 
+// unset soft demand option
+func (p *MultiAssignProblem) UnSetSoftDemand() {
+	p.isSoftDemand = false
+}
+
+func (p *MultiAssignProblem) IsSoftDemand() bool {
+	return p.isSoftDemand
+}
+
+// check for evidence of infeasibility, cheaply and without invoking the solver
+func (p *MultiAssignProblem) Screen() *Screening {
+	if p.isSoftDemand {
+		// serving nothing is feasible, unless servers need a least number of replicas
+		return p.screenMinReplicas()
+	}
+	return p.screen(false,
+		func(i int, j int) float64 { return 1 },
+		func(i int, j int) float64 { return p.arrivalRates[i] / p.targetRate(i, j) })
+}
+
 // solve problem
 func (p *MultiAssignProblem) Solve() error {
+	return p.SolveContext(context.Background())
+}
+
+// solve problem, aborting the solver when the context is done
+func (p *MultiAssignProblem) SolveContext(ctx context.Context) error {
 	// setup up problem
 	if err := p.Setup(); err != nil {
 		return err
 	}
 
 	// solve problem with timeout
-	if err := p.solveWithTimeout(); err != nil {
+	if err := p.solveContext(ctx); err != nil {
 		return err
 	}
 
 	// extract (optimal) solution
-	p.objectiveValue = p.lp.Objective()
-	vars := p.lp.Variables()
-
-	// obtain number of replicas and number of used accelerator units
-	p.numReplicas = make([][]int, p.numServers)
-	p.instancesUsed = make([]int, p.numAccelerators)
-	for i := 0; i < p.numServers; i++ {
-		p.numReplicas[i] = make([]int, p.numAccelerators)
-		v0 := i * p.numAccelerators // begin index
-		for j := 0; j < p.numAccelerators; j++ {
-			p.numReplicas[i][j] = int(math.Round(vars[v0+j]))
-			p.instancesUsed[j] += p.numReplicas[i][j] * p.numInstancesPerReplica[i][j]
-		}
+	p.setResults(roundReplicas)
+	if p.isSoftDemand {
+		p.setUnservedRates()
 	}
+	return nil
+}
 
-	// calculate number of used accelerator units
-	p.unitsUsed = make([]int, p.numAcceleratorTypes)
-	for k := 0; k < p.numAcceleratorTypes; k++ {
-		for j := 0; j < p.numAccelerators; j++ {
-			if p.acceleratorTypesMatrix[k][j] > 0 {
-				p.unitsUsed[k] += p.instancesUsed[j] * p.acceleratorTypesMatrix[k][j]
-			}
-		}
+// set dropped rates to the values of the unserved rate variables, which may exceed the shortfall of capacity
+// when unserving costs no more than serving
+func (p *MultiAssignProblem) setUnservedRates() {
+	vars := p.solution.Values
+	for i := 0; i < p.numServers; i++ {
+		dropped := math.Min(math.Max(vars[p.unserved[i]], 0), p.arrivalRates[i])
+		p.setServedRate(i, p.arrivalRates[i]-dropped)
 	}
-	return nil
 }
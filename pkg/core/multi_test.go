@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// backend returning an optimal solution with the given values
+type valuesBackend struct {
+	values []float64
+}
+
+func (b *valuesBackend) Name() string {
+	return "values"
+}
+
+func (b *valuesBackend) Solve(ctx context.Context, m *model.Model, timeout time.Duration) (*Solution, error) {
+	return &Solution{Status: OPTIMAL, Values: b.values}, nil
+}
+
+// dropped rates are the values of the unserved rate variables, rather than the shortfall of capacity
+func TestSoftDemandDroppedRates(t *testing.T) {
+	tests := []struct {
+		name        string
+		backend     Backend
+		penalty     []float64
+		served      []float64
+		dropped     []float64
+		utilization []float64
+	}{
+		{
+			name:    "unserved beyond capacity shortfall",
+			backend: &valuesBackend{values: []float64{3, 1, -1e-9, 2.5}},
+			penalty: []float64{0, 0},
+			served:  []float64{3, 0.5}, dropped: []float64{0, 2.5}, utilization: []float64{1, 0.5},
+		},
+		{
+			name:    "unserved beyond arrival rate",
+			backend: &valuesBackend{values: []float64{0, 0, 3 + 1e-9, 3}},
+			penalty: []float64{0, 0},
+			served:  []float64{0, 0}, dropped: []float64{3, 3}, utilization: []float64{0, 0},
+		},
+		{
+			name:    "solved",
+			backend: NewGoBackend(),
+			penalty: []float64{10, 20},
+			served:  []float64{1, 3}, dropped: []float64{2, 0}, utilization: []float64{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := createDiagnoseProblem(t, 4)
+			if err := p.SetSoftDemand(tt.penalty); err != nil {
+				t.Fatal(err)
+			}
+			p.SetBackend(tt.backend)
+			if err := p.Solve(); err != nil {
+				t.Fatal(err)
+			}
+			if !approxEqual(p.GetServedRates(), tt.served) || !approxEqual(p.GetDroppedRates(), tt.dropped) ||
+				!approxEqual(p.GetUtilization(), tt.utilization) {
+				t.Errorf("served %v, dropped %v, utilization %v, want %v, %v, %v", p.GetServedRates(), p.GetDroppedRates(),
+					p.GetUtilization(), tt.served, tt.dropped, tt.utilization)
+			}
+		})
+	}
+}
+
+// without soft demand, dropped rates are the shortfall of the capacity of the replicas
+func TestDroppedRatesFromCapacity(t *testing.T) {
+	p := createDiagnoseProblem(t, 6)
+	p.SetBackend(&valuesBackend{values: []float64{3, 2}})
+	if err := p.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{0, 1}; !reflect.DeepEqual(p.GetDroppedRates(), want) {
+		t.Errorf("dropped %v, want %v", p.GetDroppedRates(), want)
+	}
+}
+
+func approxEqual(got []float64, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for k := range got {
+		if math.Abs(got[k]-want[k]) > 1e-6 {
+			return false
+		}
+	}
+	return true
+}
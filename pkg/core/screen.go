@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// tolerance when comparing units needed with units available
+const screenTol = 1e-9
+
+// Result of pre-solve feasibility screening: evidence that a problem is infeasible, found without a solver.
+// Screening is cheap but incomplete; a problem that passes may still be found infeasible by the solver.
+type Screening struct {
+	Feasible  bool             // no evidence of infeasibility found
+	Servers   []ServerConflict // servers that cannot use any accelerator
+	Shortages []TypeShortage   // accelerator types with fewer units available than servers need
+}
+
+// server that cannot use any accelerator
+type ServerConflict struct {
+	Server  int
+	Blocked []PairShortage // why each accelerator with a positive rate cannot be used, empty if there is none
+}
+
+// accelerator that a server cannot use, since it needs more units of a type than available
+type PairShortage struct {
+	Accelerator int
+	Type        int
+	UnitsNeeded float64 // least number of units needed when using the accelerator
+	UnitsAvail  int
+}
+
+// accelerator type with fewer units available than the least number of units needed by all servers
+type TypeShortage struct {
+	Type        int
+	UnitsNeeded float64
+	UnitsAvail  int
+}
+
+// explanation of the infeasibility, one line per conflict
+func (s *Screening) String() string {
+	if s.Feasible {
+		return "no infeasibility found"
+	}
+	var lines []string
+	for _, c := range s.Servers {
+		if len(c.Blocked) == 0 {
+			lines = append(lines, fmt.Sprintf("server %d: no accelerator with a positive rate", c.Server))
+			continue
+		}
+		reasons := make([]string, len(c.Blocked))
+		for k, b := range c.Blocked {
+			reasons[k] = fmt.Sprintf("accelerator %d needs %v units of type %d, %d available",
+				b.Accelerator, b.UnitsNeeded, b.Type, b.UnitsAvail)
+		}
+		lines = append(lines, fmt.Sprintf("server %d: no accelerator has enough units: %s", c.Server, strings.Join(reasons, ", ")))
+	}
+	for _, t := range s.Shortages {
+		lines = append(lines, fmt.Sprintf("type %d: servers need at least %v units, %d available", t.Type, t.UnitsNeeded, t.UnitsAvail))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// screen problem for infeasibility, given for each server and accelerator pair with a positive rate:
+// the least number of replicas if the server uses the accelerator at all, and the number of replicas
+// if the server uses only that accelerator, a lower bound when fractional;
+// servers without load need no accelerator, unless all servers are assigned one
+func (p *BaseProblem) screen(assignAll bool, minReplicas func(i int, j int) float64, aloneReplicas func(i int, j int) float64) *Screening {
+	return p.screenServers(func(i int) bool { return assignAll || p.arrivalRates[i] > 0 }, minReplicas, aloneReplicas)
+}
+
+// screen problem for infeasibility when demand may be unserved, so that no replicas is feasible
+// unless servers need a least number of replicas: only those replicas are screened
+func (p *BaseProblem) screenMinReplicas() *Screening {
+	return p.screenServers(func(i int) bool { return p.minServerReplicas(i) > 0 },
+		func(i int, j int) float64 { return 1 },
+		func(i int, j int) float64 { return float64(p.minServerReplicas(i)) })
+}
+
+// screen the servers needing an accelerator, see screen
+func (p *BaseProblem) screenServers(needsAccelerator func(i int) bool, minReplicas func(i int, j int) float64,
+	aloneReplicas func(i int, j int) float64) *Screening {
+	s := &Screening{}
+	numTypes := 0
+	if p.isLimited {
+		numTypes = p.numAcceleratorTypes
+	}
+	unitsNeeded := make([]float64, numTypes) // least units needed by all servers
+
+	for i := 0; i < p.numServers; i++ {
+		if !needsAccelerator(i) {
+			continue
+		}
+		var blocked []PairShortage
+		usable := make([]int, 0, p.numAccelerators)
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.ratePerReplica[i][j] == 0 {
+				continue
+			}
+			if shortage, ok := p.pairShortage(i, j, minReplicas(i, j)); ok {
+				blocked = append(blocked, shortage)
+				continue
+			}
+			usable = append(usable, j)
+		}
+		if len(usable) == 0 {
+			s.Servers = append(s.Servers, ServerConflict{Server: i, Blocked: blocked})
+			continue
+		}
+
+		// least units of each type the server needs, using the usable accelerator needing the fewest
+		for k := 0; k < numTypes; k++ {
+			least := math.Inf(1)
+			for _, j := range usable {
+				least = math.Min(least, aloneReplicas(i, j)*float64(p.numInstancesPerReplica[i][j]*p.acceleratorTypesMatrix[k][j]))
+			}
+			unitsNeeded[k] += least
+		}
+	}
+
+	for k := 0; k < numTypes; k++ {
+		if unitsNeeded[k] > float64(p.unitsAvail[k])+screenTol {
+			s.Shortages = append(s.Shortages, TypeShortage{Type: k, UnitsNeeded: unitsNeeded[k], UnitsAvail: p.unitsAvail[k]})
+		}
+	}
+	s.Feasible = len(s.Servers) == 0 && len(s.Shortages) == 0
+	return s
+}
+
+// first accelerator type with fewer units available than needed by a number of replicas of a pair, if limited
+func (p *BaseProblem) pairShortage(i int, j int, replicas float64) (PairShortage, bool) {
+	if !p.isLimited {
+		return PairShortage{}, false
+	}
+	for k := 0; k < p.numAcceleratorTypes; k++ {
+		needed := replicas * float64(p.numInstancesPerReplica[i][j]*p.acceleratorTypesMatrix[k][j])
+		if needed > float64(p.unitsAvail[k])+screenTol {
+			return PairShortage{Accelerator: j, Type: k, UnitsNeeded: needed, UnitsAvail: p.unitsAvail[k]}, true
+		}
+	}
+	return PairShortage{}, false
+}
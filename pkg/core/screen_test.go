@@ -0,0 +1,77 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+// screening of problems where demand may be unserved, of the least numbers of replicas of servers
+func TestScreenMinReplicas(t *testing.T) {
+	create := map[string]func(numInstancesPerReplica [][]int) (Problem, error){
+		"soft demand": func(numInstancesPerReplica [][]int) (Problem, error) {
+			p, err := CreateMultiAssignProblem(2, 1, []float64{1}, numInstancesPerReplica, [][]float64{{1}, {1}}, []float64{3, 3})
+			if err != nil {
+				return nil, err
+			}
+			return p, p.SetSoftDemand([]float64{10, 10})
+		},
+		"throughput": func(numInstancesPerReplica [][]int) (Problem, error) {
+			return CreateThroughputProblem(2, 1, []float64{1}, numInstancesPerReplica, [][]float64{{1}, {1}}, []float64{3, 3})
+		},
+		"fairness": func(numInstancesPerReplica [][]int) (Problem, error) {
+			return CreateFairnessProblem(2, 1, []float64{1}, numInstancesPerReplica, [][]float64{{1}, {1}}, []float64{3, 3})
+		},
+	}
+	tests := []struct {
+		name                   string
+		numInstancesPerReplica [][]int
+		minReplicas            []int
+		want                   *Screening
+	}{
+		{
+			name:                   "no replica bounds",
+			numInstancesPerReplica: [][]int{{5}, {5}},
+			want:                   &Screening{Feasible: true},
+		},
+		{
+			name:                   "enough units",
+			numInstancesPerReplica: [][]int{{1}, {1}},
+			minReplicas:            []int{2, 2},
+			want:                   &Screening{Feasible: true},
+		},
+		{
+			name:                   "type shortage",
+			numInstancesPerReplica: [][]int{{1}, {1}},
+			minReplicas:            []int{3, 3},
+			want:                   &Screening{Shortages: []TypeShortage{{Type: 0, UnitsNeeded: 6, UnitsAvail: 4}}},
+		},
+		{
+			name:                   "server conflict",
+			numInstancesPerReplica: [][]int{{5}, {5}},
+			minReplicas:            []int{1, 0},
+			want: &Screening{Servers: []ServerConflict{{Server: 0,
+				Blocked: []PairShortage{{Accelerator: 0, Type: 0, UnitsNeeded: 5, UnitsAvail: 4}}}}},
+		},
+	}
+	for kind, createProblem := range create {
+		for _, tt := range tests {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				p, err := createProblem(tt.numInstancesPerReplica)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := p.SetLimited(1, []int{4}, [][]int{{1}}); err != nil {
+					t.Fatal(err)
+				}
+				if tt.minReplicas != nil {
+					if err := p.SetReplicaBounds(tt.minReplicas, nil, nil); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if got := p.Screen(); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Screen() = %+v, want %+v", got, tt.want)
+				}
+			})
+		}
+	}
+}
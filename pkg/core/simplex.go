@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"math"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+const (
+	simplexEps           = 1e-9 // pivot and feasibility tolerance
+	simplexCheckInterval = 64   // number of pivots between context checks
+	simplexStallLimit    = 50   // number of degenerate pivots before switching to Bland's rule
+)
+
+// LP in standard form: minimize c.y subject to a y (ops) b, y >= 0
+type standardLP struct {
+	c   []float64
+	a   [][]float64
+	ops []model.ConstraintType
+	b   []float64
+}
+
+// dense two-phase primal simplex tableau
+type tableau struct {
+	t         [][]float64 // constraint rows followed by objective row, last column is the right hand side
+	basis     []int       // basic variable of each constraint row
+	numCols   int         // number of columns, excluding the right hand side
+	numStruct int         // number of structural columns
+	isArt     []bool      // artificial columns
+}
+
+// solve LP using the two-phase simplex method, returning the status, objective value, and variable values
+func (lp *standardLP) solve(ctx context.Context) (SolutionType, float64, []float64) {
+	m := len(lp.a)
+	n := len(lp.c)
+
+	// make right hand sides nonnegative
+	a := make([][]float64, m)
+	b := make([]float64, m)
+	ops := make([]model.ConstraintType, m)
+	numSlack, numArt := 0, 0
+	for i := 0; i < m; i++ {
+		a[i], b[i], ops[i] = lp.a[i], lp.b[i], lp.ops[i]
+		if b[i] < 0 {
+			a[i] = make([]float64, n)
+			for j, v := range lp.a[i] {
+				a[i][j] = -v
+			}
+			b[i] = -b[i]
+			switch ops[i] {
+			case model.LE:
+				ops[i] = model.GE
+			case model.GE:
+				ops[i] = model.LE
+			}
+		}
+		switch ops[i] {
+		case model.LE:
+			numSlack++
+		case model.GE:
+			numSlack++
+			numArt++
+		case model.EQ:
+			numArt++
+		}
+	}
+
+	// build tableau: structural, slack, then artificial columns
+	numCols := n + numSlack + numArt
+	tb := &tableau{
+		t:         make([][]float64, m+1),
+		basis:     make([]int, m),
+		numCols:   numCols,
+		numStruct: n,
+		isArt:     make([]bool, numCols),
+	}
+	slack, art := n, n+numSlack
+	for i := 0; i < m; i++ {
+		row := make([]float64, numCols+1)
+		copy(row, a[i])
+		row[numCols] = b[i]
+		switch ops[i] {
+		case model.LE:
+			row[slack] = 1
+			tb.basis[i] = slack
+			slack++
+		case model.GE:
+			row[slack] = -1
+			slack++
+			row[art] = 1
+			tb.isArt[art] = true
+			tb.basis[i] = art
+			art++
+		case model.EQ:
+			row[art] = 1
+			tb.isArt[art] = true
+			tb.basis[i] = art
+			art++
+		}
+		tb.t[i] = row
+	}
+	tb.t[m] = make([]float64, numCols+1)
+
+	// phase 1: minimize sum of artificial variables
+	if numArt > 0 {
+		cost := make([]float64, numCols)
+		for j := range cost {
+			if tb.isArt[j] {
+				cost[j] = 1
+			}
+		}
+		tb.setObjective(cost)
+		if status := tb.iterate(ctx, false); status != OPTIMAL {
+			return status, 0, nil
+		}
+		if -tb.t[m][numCols] > 1e-7 {
+			return INFEASIBLE, 0, nil
+		}
+		tb.removeArtificials()
+	}
+
+	// phase 2: minimize original objective
+	cost := make([]float64, numCols)
+	copy(cost, lp.c)
+	tb.setObjective(cost)
+	if status := tb.iterate(ctx, true); status != OPTIMAL {
+		return status, 0, nil
+	}
+
+	y := make([]float64, n)
+	for i, j := range tb.basis {
+		if j < n {
+			y[j] = tb.t[i][numCols]
+		}
+	}
+	return OPTIMAL, -tb.t[m][numCols], y
+}
+
+// set objective row to the reduced costs of the current basis
+func (tb *tableau) setObjective(cost []float64) {
+	m := len(tb.basis)
+	obj := tb.t[m]
+	copy(obj, cost)
+	obj[tb.numCols] = 0
+	for i, j := range tb.basis {
+		if cj := cost[j]; cj != 0 {
+			for k, v := range tb.t[i] {
+				obj[k] -= cj * v
+			}
+		}
+	}
+}
+
+// pivot until optimal, unbounded, or aborted; artificial columns may not enter in phase 2
+func (tb *tableau) iterate(ctx context.Context, phase2 bool) SolutionType {
+	m := len(tb.basis)
+	obj := tb.t[m]
+	maxIter := 50 * (m + tb.numCols)
+	stall := 0
+	for iter := 0; iter < maxIter; iter++ {
+		if iter%simplexCheckInterval == 0 && ctx.Err() != nil {
+			return USERABORT
+		}
+
+		// entering column: most negative reduced cost, or the first negative one when stalling
+		enter := -1
+		best := -simplexEps
+		for j := 0; j < tb.numCols; j++ {
+			if phase2 && tb.isArt[j] {
+				continue
+			}
+			if obj[j] < best {
+				enter = j
+				if stall >= simplexStallLimit {
+					break
+				}
+				best = obj[j]
+			}
+		}
+		if enter < 0 {
+			return OPTIMAL
+		}
+
+		// leaving row: minimum ratio, ties broken by smallest basic variable
+		leave := -1
+		ratio := math.Inf(1)
+		for i := 0; i < m; i++ {
+			if v := tb.t[i][enter]; v > simplexEps {
+				r := tb.t[i][tb.numCols] / v
+				if r < ratio-simplexEps || (r < ratio+simplexEps && leave >= 0 && tb.basis[i] < tb.basis[leave]) {
+					leave = i
+					ratio = r
+				}
+			}
+		}
+		if leave < 0 {
+			return UNBOUNDED
+		}
+
+		if ratio < simplexEps {
+			stall++
+		} else {
+			stall = 0
+		}
+		tb.pivot(leave, enter)
+	}
+	return NUMFAILURE
+}
+
+func (tb *tableau) pivot(row int, col int) {
+	pr := tb.t[row]
+	pv := pr[col]
+	for k := range pr {
+		pr[k] /= pv
+	}
+	for i, r := range tb.t {
+		if i == row {
+			continue
+		}
+		if f := r[col]; f != 0 {
+			for k, v := range pr {
+				if v != 0 {
+					r[k] -= f * v
+				}
+			}
+		}
+	}
+	tb.basis[row] = col
+}
+
+// drive artificial variables out of the basis after phase 1, where possible
+func (tb *tableau) removeArtificials() {
+	for i, j := range tb.basis {
+		if !tb.isArt[j] {
+			continue
+		}
+		for k := 0; k < tb.numCols; k++ {
+			if !tb.isArt[k] && math.Abs(tb.t[i][k]) > simplexEps {
+				tb.pivot(i, k)
+				break
+			}
+		}
+		// otherwise the row is redundant and the artificial variable stays basic at zero
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+func TestStandardLPSolve(t *testing.T) {
+	le, ge, eq := model.LE, model.GE, model.EQ
+	tests := []struct {
+		name   string
+		lp     standardLP
+		status SolutionType
+		obj    float64
+		y      []float64 // expected values, not checked if nil
+	}{
+		{
+			name: "inequalities",
+			lp: standardLP{
+				c:   []float64{-3, -5},
+				a:   [][]float64{{1, 0}, {0, 2}, {3, 2}},
+				ops: []model.ConstraintType{le, le, le},
+				b:   []float64{4, 12, 18},
+			},
+			status: OPTIMAL, obj: -36, y: []float64{2, 6},
+		},
+		{
+			name: "negative right hand side",
+			lp: standardLP{
+				c:   []float64{1, 1},
+				a:   [][]float64{{-1, -1}},
+				ops: []model.ConstraintType{le},
+				b:   []float64{-2},
+			},
+			status: OPTIMAL, obj: 2,
+		},
+		{
+			// cycles under the most negative reduced cost rule without Bland's rule (Beale)
+			name: "degenerate",
+			lp: standardLP{
+				c:   []float64{-0.75, 150, -0.02, 6},
+				a:   [][]float64{{0.25, -60, -0.04, 9}, {0.5, -90, -0.02, 3}, {0, 0, 1, 0}},
+				ops: []model.ConstraintType{le, le, le},
+				b:   []float64{0, 0, 1},
+			},
+			status: OPTIMAL, obj: -0.05, y: []float64{0.04, 0, 1, 0},
+		},
+		{
+			// redundant equality leaves an artificial variable basic at zero
+			name: "redundant equality",
+			lp: standardLP{
+				c:   []float64{1, 0},
+				a:   [][]float64{{1, 1}, {2, 2}},
+				ops: []model.ConstraintType{eq, eq},
+				b:   []float64{2, 4},
+			},
+			status: OPTIMAL, obj: 0, y: []float64{0, 2},
+		},
+		{
+			name: "greater or equal",
+			lp: standardLP{
+				c:   []float64{2, 3},
+				a:   [][]float64{{1, 1}, {1, -1}},
+				ops: []model.ConstraintType{ge, le},
+				b:   []float64{4, 2},
+			},
+			status: OPTIMAL, obj: 9, y: []float64{3, 1},
+		},
+		{
+			name: "infeasible",
+			lp: standardLP{
+				c:   []float64{1, 1},
+				a:   [][]float64{{1, 1}, {1, 1}},
+				ops: []model.ConstraintType{le, ge},
+				b:   []float64{1, 2},
+			},
+			status: INFEASIBLE,
+		},
+		{
+			name: "unbounded",
+			lp: standardLP{
+				c:   []float64{-1, 0},
+				a:   [][]float64{{1, -1}},
+				ops: []model.ConstraintType{le},
+				b:   []float64{1},
+			},
+			status: UNBOUNDED,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, obj, y := tt.lp.solve(context.Background())
+			if status != tt.status {
+				t.Fatalf("status = %v, want %v", status, tt.status)
+			}
+			if status != OPTIMAL {
+				return
+			}
+			if math.Abs(obj-tt.obj) > 1e-7 {
+				t.Errorf("objective = %v, want %v", obj, tt.obj)
+			}
+			for j, want := range tt.y {
+				if math.Abs(y[j]-want) > 1e-7 {
+					t.Errorf("y[%d] = %v, want %v", j, y[j], want)
+				}
+			}
+		})
+	}
+}
+
+func TestStandardLPSolveCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	lp := standardLP{
+		c:   []float64{-1},
+		a:   [][]float64{{1}},
+		ops: []model.ConstraintType{model.LE},
+		b:   []float64{1},
+	}
+	if status, _, _ := lp.solve(ctx); status != USERABORT {
+		t.Errorf("status = %v, want %v", status, USERABORT)
+	}
+}
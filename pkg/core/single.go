@@ -1,18 +1,24 @@
 package core
 
 import (
+	"context"
+	"fmt"
 	"math"
 
-	"github.com/draffensperger/golp"
+	"github.com/llm-inferno/lpsolve/pkg/model"
 )
 
 // A special MILP problem with binary variables
 //   - assign one accelerator kind to a server
+//   - with a calculated number of replicas, or optionally a variable number linked to the assignment
 type SingleAssignProblem struct {
 	BaseProblem
 
 	// calculated maximum number of replicas [numServers][numAccelerators]
 	maxNumReplicas [][]int
+
+	// number of replicas is a variable, rather than calculated
+	isVariableReplicas bool
 }
 
 // create an instance of an assignment problem
@@ -26,117 +32,189 @@ func CreateSingleAssignProblem(numServers int, numAccelerators int, instanceCost
 	p := &SingleAssignProblem{
 		BaseProblem: *bp}
 	p.BaseProblem.Setup = p.Setup
+	p.BaseProblem.Screen = p.Screen
 	p.BaseProblem.Solve = p.Solve
+	p.BaseProblem.SolveContext = p.SolveContext
 	return p, nil
 }
 
+// set variable replicas option: the number of replicas of an assigned pair is an integer variable,
+// linked to a binary assignment variable, rather than calculated before solving
+func (p *SingleAssignProblem) SetVariableReplicas() {
+	p.isVariableReplicas = true
+}
+
+// unset variable replicas option
+func (p *SingleAssignProblem) UnSetVariableReplicas() {
+	p.isVariableReplicas = false
+}
+
+func (p *SingleAssignProblem) IsVariableReplicas() bool {
+	return p.isVariableReplicas
+}
+
 // setup constraints and objective function
 func (p *SingleAssignProblem) Setup() error {
-	// define LP problem
-	numVars := p.numServers * p.numAccelerators
-	p.lp = golp.NewLP(0, numVars)
-	for k := 0; k < numVars; k++ {
-		p.lp.SetBinary(k, true)
+	if p.isVariableReplicas {
+		return p.setupVariableReplicas()
 	}
 
-	// excluded infeasible variables (for a given server accelerator pair)
-	excluded := make([]float64, numVars)
-
 	// calculate max number of replicas
 	p.maxNumReplicas = make([][]int, p.numServers)
 	for i := 0; i < p.numServers; i++ {
 		p.maxNumReplicas[i] = make([]int, p.numAccelerators)
 		for j := 0; j < p.numAccelerators; j++ {
 			if p.ratePerReplica[i][j] > 0 {
-				p.maxNumReplicas[i][j] = int(math.Ceil(p.arrivalRates[i] / p.ratePerReplica[i][j]))
-			} else {
-				excluded[i*p.numAccelerators+j] = 1
+				p.maxNumReplicas[i][j] = p.numReplicasNeeded(i, j)
 			}
 		}
 	}
 	// fmt.Println(utils.Pretty2D("maxNumReplicas", p.maxNumReplicas))
 
+	// define LP problem: assignment of server and accelerator pairs;
+	// pairs needing more replicas than allowed by the replica bounds cannot be assigned, and have no variable
+	p.model = model.NewModel(0)
+	p.addPairVarsExcept(model.Binary, func(i int, j int) bool {
+		return !p.withinReplicaBounds(i, j, p.maxNumReplicas[i][j])
+	})
+
 	// set objective function: cost coefficients
-	costVector := make([]float64, numVars)
+	cost := model.NewExpr()
 	for i := 0; i < p.numServers; i++ {
-		v0 := i * p.numAccelerators // begin index
 		for j := 0; j < p.numAccelerators; j++ {
-			costVector[v0+j] = float64(p.numInstancesPerReplica[i][j]*p.maxNumReplicas[i][j]) * p.instanceCost[j]
+			if p.x[i][j] == noVar {
+				continue
+			}
+			cost.Add(float64(p.numInstancesPerReplica[i][j]*p.maxNumReplicas[i][j])*p.instanceCost[j], p.x[i][j])
 		}
 	}
-	p.lp.SetObjFn(costVector)
-	// fmt.Println(utils.Pretty1D("costVector", costVector))
+	if err := p.model.SetObjective(cost); err != nil {
+		return err
+	}
 
 	// set binary assignment constraints - only one variable set to one per server
+	p.demandRows = make([]int, p.numServers)
 	for i := 0; i < p.numServers; i++ {
-		assignVector := make([]float64, numVars)
-		v0 := i * p.numAccelerators // begin index
+		assign := model.NewExpr()
 		for j := 0; j < p.numAccelerators; j++ {
-			assignVector[v0+j] = 1
+			if p.x[i][j] != noVar {
+				assign.Add(1, p.x[i][j])
+			}
+		}
+		p.demandRows[i] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("assign_server%d", i), assign, model.EQ, 1); err != nil {
+			return err
 		}
-		p.lp.AddConstraint(assignVector, golp.EQ, 1)
-		// fmt.Printf("i=%d; %s tot=%v\n", i, utils.Pretty1D("assignVector", assignVector), 1)
 	}
 
 	// set count limit constraints
-	if p.isLimited {
-		for k := 0; k < p.numAcceleratorTypes; k++ {
-			countVector := make([]float64, numVars)
-			for i := 0; i < p.numServers; i++ {
-				for j := 0; j < p.numAccelerators; j++ {
-					if p.acceleratorTypesMatrix[k][j] > 0 {
-						idx := i*p.numAccelerators + j
-						countVector[idx] = float64(p.numInstancesPerReplica[i][j] * p.maxNumReplicas[i][j] * p.acceleratorTypesMatrix[k][j])
-					}
-				}
+	if err := p.addCapConstraints(func(i int, j int) float64 {
+		return float64(p.numInstancesPerReplica[i][j] * p.maxNumReplicas[i][j])
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setup constraints and objective function, with a variable number of replicas
+func (p *SingleAssignProblem) setupVariableReplicas() error {
+	// define LP problem: number of replicas and assignment of server and accelerator pairs
+	p.model = model.NewModel(0)
+	p.addPairVars(model.Integer)
+	p.addSelectionVars()
+
+	// set objective function: cost coefficients
+	if err := p.model.SetObjective(p.replicaCost()); err != nil {
+		return err
+	}
+
+	// set rate constraints: rate coefficients
+	p.demandRows = make([]int, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		rate := model.NewExpr()
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] != noVar {
+				rate.Add(p.targetRate(i, j), p.x[i][j])
 			}
-			p.lp.AddConstraint(countVector, golp.LE, float64(p.unitsAvail[k]))
-			// fmt.Printf("k=%d; %s avail=%d\n", k, utils.Pretty1D("countVector", countVector), p.unitsAvail[k])
+		}
+		p.demandRows[i] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("rate_server%d", i), rate, model.GE, p.arrivalRates[i]); err != nil {
+			return err
 		}
 	}
 
-	p.lp.AddConstraint(excluded, golp.EQ, 0)
-	// fmt.Println(utils.Pretty1D("excluded", excluded))
+	// set link constraints: replicas only of assigned pairs, and binary assignment constraints - only one pair per server
+	if err := p.addLinkConstraints(); err != nil {
+		return err
+	}
+	for i := 0; i < p.numServers; i++ {
+		if err := p.model.AddConstr(fmt.Sprintf("assign_server%d", i), p.selection(i), model.EQ, 1); err != nil {
+			return err
+		}
+	}
+
+	// set replica bounds
+	if err := p.addReplicaBounds(); err != nil {
+		return err
+	}
+
+	// set count limit constraints
+	if err := p.addCapConstraints(func(i int, j int) float64 { return float64(p.numInstancesPerReplica[i][j]) }); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// check for evidence of infeasibility, cheaply and without invoking the solver
+func (p *SingleAssignProblem) Screen() *Screening {
+	replicas := func(i int, j int) float64 { return float64(p.numReplicasNeeded(i, j)) }
+	return p.screen(true, replicas, replicas)
+}
+
+// number of replicas of a server if assigned an accelerator (with a positive rate):
+// enough for its arrival rate at the target utilization, and at least its least number of replicas
+func (p *SingleAssignProblem) numReplicasNeeded(i int, j int) int {
+	replicas := int(math.Ceil(p.arrivalRates[i] / p.targetRate(i, j)))
+	return max(replicas, p.minServerReplicas(i))
+}
+
+// check if a number of replicas of a server and accelerator pair is within the replica bounds
+func (p *SingleAssignProblem) withinReplicaBounds(i int, j int, replicas int) bool {
+	if maxServer, ok := p.maxServerReplicas(i); ok && replicas > maxServer {
+		return false
+	}
+	if maxPair, ok := p.maxReplicasOfPair(i, j); ok && replicas > maxPair {
+		return false
+	}
+	return true
+}
+
 // solve problem
 func (p *SingleAssignProblem) Solve() error {
+	return p.SolveContext(context.Background())
+}
+
+// solve problem, aborting the solver when the context is done
+func (p *SingleAssignProblem) SolveContext(ctx context.Context) error {
 	// setup up problem
 	if err := p.Setup(); err != nil {
 		return err
 	}
 
 	// solve problem with timeout
-	if err := p.solveWithTimeout(); err != nil {
+	if err := p.solveContext(ctx); err != nil {
 		return err
 	}
 
 	// extract (optimal) solution
-	p.objectiveValue = p.lp.Objective()
-	vars := p.lp.Variables()
-
-	// obtain number of replicas and number of used accelerator units
-	p.numReplicas = make([][]int, p.numServers)
-	p.instancesUsed = make([]int, p.numAccelerators)
-	for i := 0; i < p.numServers; i++ {
-		p.numReplicas[i] = make([]int, p.numAccelerators)
-		v0 := i * p.numAccelerators // begin index
-		for j := 0; j < p.numAccelerators; j++ {
-			p.numReplicas[i][j] = int(math.Round(vars[v0+j])) * p.maxNumReplicas[i][j]
-			p.instancesUsed[j] += p.numReplicas[i][j] * p.numInstancesPerReplica[i][j]
-		}
-	}
-
-	// calculate number of used accelerator types
-	p.unitsUsed = make([]int, p.numAcceleratorTypes)
-	for k := 0; k < p.numAcceleratorTypes; k++ {
-		for j := 0; j < p.numAccelerators; j++ {
-			if p.acceleratorTypesMatrix[k][j] > 0 {
-				p.unitsUsed[k] += p.instancesUsed[j] * p.acceleratorTypesMatrix[k][j]
-			}
-		}
+	if p.isVariableReplicas {
+		p.setResults(roundReplicas)
+		return nil
 	}
+	p.setResults(func(i int, j int, value float64) int {
+		return int(math.Round(value)) * p.maxNumReplicas[i][j]
+	})
 	return nil
 }
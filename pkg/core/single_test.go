@@ -0,0 +1,149 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pairs needing more replicas than allowed have no variable, in the model and so in exported models
+func TestSingleReplicaBoundsExcludePairs(t *testing.T) {
+	// accelerator 0 is cheaper, but needs four replicas
+	p, err := CreateSingleAssignProblem(1, 2, []float64{1, 10}, [][]int{{1, 1}}, [][]float64{{1, 4}}, []float64{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetReplicaBounds(nil, []int{2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	p.SetBackend(NewGoBackend())
+	if err := p.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.GetNumReplicas(), [][]int{{0, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("number of replicas = %v, want %v", got, want)
+	}
+
+	for format, write := range map[string]func(*bytes.Buffer) error{
+		"LP":  func(b *bytes.Buffer) error { return p.WriteLP(b) },
+		"MPS": func(b *bytes.Buffer) error { return p.WriteMPS(b) },
+	} {
+		var b bytes.Buffer
+		if err := write(&b); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(b.String(), "x_server0_acc0") {
+			t.Errorf("%s model has a variable of the excluded pair:\n%s", format, b.String())
+		}
+		if !strings.Contains(b.String(), "x_server0_acc1") {
+			t.Errorf("%s model has no variable of the allowed pair:\n%s", format, b.String())
+		}
+	}
+}
+
+// a variable number of replicas, minimizing cost, takes the least number serving the arrival rate within the bounds
+func TestSingleVariableReplicas(t *testing.T) {
+	const numServers, numAccelerators = 3, 3
+	for seed := int64(1); seed <= 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		instanceCost := make([]float64, numAccelerators)
+		unitsAvail := make([]int, numAccelerators)
+		acceleratorTypesMatrix := make([][]int, numAccelerators)
+		for j := 0; j < numAccelerators; j++ {
+			instanceCost[j] = 1 + float64(rng.Intn(5))
+			unitsAvail[j] = 4 + rng.Intn(12)
+			acceleratorTypesMatrix[j] = make([]int, numAccelerators)
+			acceleratorTypesMatrix[j][j] = 1
+		}
+		numInstancesPerReplica := make([][]int, numServers)
+		ratePerReplica := make([][]float64, numServers)
+		arrivalRates := make([]float64, numServers)
+		minReplicas := make([]int, numServers)
+		maxReplicas := make([]int, numServers)
+		for i := 0; i < numServers; i++ {
+			numInstancesPerReplica[i] = make([]int, numAccelerators)
+			ratePerReplica[i] = make([]float64, numAccelerators)
+			for j := 0; j < numAccelerators; j++ {
+				numInstancesPerReplica[i][j] = 1 + rng.Intn(3)
+				ratePerReplica[i][j] = float64(rng.Intn(4))
+			}
+			arrivalRates[i] = float64(rng.Intn(8))
+			minReplicas[i] = rng.Intn(3)
+			maxReplicas[i] = minReplicas[i] + rng.Intn(6)
+		}
+		targetUtilization := []float64{1, 0.8, 0.5}
+
+		var problems [2]*SingleAssignProblem
+		var errs [2]error
+		for k := range problems {
+			p, err := CreateSingleAssignProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+				ratePerReplica, arrivalRates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if k == 1 {
+				p.SetVariableReplicas()
+			}
+			if err := p.SetLimited(numAccelerators, unitsAvail, acceleratorTypesMatrix); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.SetReplicaBounds(minReplicas, maxReplicas, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.SetTargetUtilization(targetUtilization); err != nil {
+				t.Fatal(err)
+			}
+			p.SetBackend(NewGoBackend())
+			problems[k], errs[k] = p, p.Solve()
+			if errs[k] != nil && !errors.Is(errs[k], ErrInfeasible) {
+				t.Fatalf("seed %d: %v", seed, errs[k])
+			}
+		}
+
+		calculated, variable := problems[0], problems[1]
+		switch {
+		case (errs[0] == nil) != (errs[1] == nil):
+			t.Errorf("seed %d: calculated replicas error %v, variable replicas error %v", seed, errs[0], errs[1])
+		case errs[0] == nil && math.Abs(calculated.GetObjectiveValue()-variable.GetObjectiveValue()) > 1e-6:
+			t.Errorf("seed %d: calculated replicas cost %v, variable replicas cost %v",
+				seed, calculated.GetObjectiveValue(), variable.GetObjectiveValue())
+		case errs[0] == nil:
+			for i, replicas := range variable.GetNumReplicas() {
+				total, kinds := 0, 0
+				for _, r := range replicas {
+					total += r
+					if r > 0 {
+						kinds++
+					}
+				}
+				if kinds > 1 || total < minReplicas[i] || total > maxReplicas[i] {
+					t.Errorf("seed %d: server %d has replicas %v, want one kind within [%d, %d]",
+						seed, i, replicas, minReplicas[i], maxReplicas[i])
+				}
+			}
+		}
+	}
+}
+
+// the number of replicas is an integer variable, linked to a binary assignment variable
+func TestSingleVariableReplicasModel(t *testing.T) {
+	p, err := CreateSingleAssignProblem(1, 2, []float64{1, 10}, [][]int{{1, 1}}, [][]float64{{1, 4}}, []float64{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetVariableReplicas()
+	var b bytes.Buffer
+	if err := p.WriteLP(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"rate_server0:", "link_server0_acc0:", "link_server0_acc1:", "assign_server0:",
+		"int x_server0_acc0,x_server0_acc1", "y_server0_acc0"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("LP model has no %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// MILP problem maximizing the weighted served arrival rate, with potential multiple kinds of accelerators
+// assigned to a server, and then minimizing cost
+type ThroughputProblem struct {
+	BaseProblem
+
+	priorities []float64   // weight of served rate [numServers], one if nil
+	served     []model.Var // served arrival rate variables [numServers]
+
+	weightedServedRate float64 // resulting weighted served rate
+}
+
+// create an instance of the problem
+func CreateThroughputProblem(numServers int, numAccelerators int, instanceCost []float64, numInstancesPerReplica [][]int,
+	ratePerReplica [][]float64, arrivalRates []float64) (*ThroughputProblem, error) {
+	bp, err := CreateBaseProblem(numServers, numAccelerators, instanceCost, numInstancesPerReplica,
+		ratePerReplica, arrivalRates)
+	if err != nil {
+		return nil, err
+	}
+	p := &ThroughputProblem{
+		BaseProblem: *bp}
+	p.BaseProblem.Setup = p.Setup
+	p.BaseProblem.Screen = p.Screen
+	p.BaseProblem.Solve = p.Solve
+	p.BaseProblem.SolveContext = p.SolveContext
+	return p, nil
+}
+
+// set priorities of servers, weighting their served rates [numServers]
+func (p *ThroughputProblem) SetPriorities(priorities []float64) error {
+	if err := validateWeights("priorities", p.numServers, priorities); err != nil {
+		return err
+	}
+	p.priorities = priorities
+	return nil
+}
+
+func (p *ThroughputProblem) GetPriorities() []float64 {
+	return p.priorities
+}
+
+// weight of served rate of server
+func (p *ThroughputProblem) priority(i int) float64 {
+	if p.priorities == nil {
+		return 1
+	}
+	return p.priorities[i]
+}
+
+// setup constraints and objective function
+func (p *ThroughputProblem) Setup() error {
+	// define LP problem: number of replicas for server and accelerator pairs, and served rate of servers
+	p.model = model.NewModel(0)
+	p.addPairVars(model.Integer)
+	p.served = make([]model.Var, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		p.served[i] = p.model.AddBoundedVar(fmt.Sprintf("served_server%d", i), model.Continuous, 0, p.arrivalRates[i])
+	}
+
+	// set objective function: weighted served rate
+	if err := p.model.SetObjective(p.throughput()); err != nil {
+		return err
+	}
+	p.model.SetMaximize()
+
+	// set rate constraints: served rate at most rate of replicas
+	p.demandRows = make([]int, p.numServers)
+	for i := 0; i < p.numServers; i++ {
+		rate := model.NewExpr()
+		for j := 0; j < p.numAccelerators; j++ {
+			if p.x[i][j] != noVar {
+				rate.Add(p.targetRate(i, j), p.x[i][j])
+			}
+		}
+		rate.Add(-1, p.served[i])
+		p.demandRows[i] = p.model.NumConstraints()
+		if err := p.model.AddConstr(fmt.Sprintf("rate_server%d", i), rate, model.GE, 0); err != nil {
+			return err
+		}
+	}
+
+	// set replica bounds
+	if err := p.addReplicaBounds(); err != nil {
+		return err
+	}
+
+	// set count limit constraints
+	if err := p.addCapConstraints(func(i int, j int) float64 { return float64(p.numInstancesPerReplica[i][j]) }); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// weighted served rate
+func (p *ThroughputProblem) throughput() *model.Expr {
+	throughput := model.NewExpr()
+	for i := 0; i < p.numServers; i++ {
+		throughput.Add(p.priority(i), p.served[i])
+	}
+	return throughput
+}
+
+// check for evidence of infeasibility: as serving nothing is feasible, only of the least numbers of replicas of servers
+func (p *ThroughputProblem) Screen() *Screening {
+	return p.screenMinReplicas()
+}
+
+// solve problem
+func (p *ThroughputProblem) Solve() error {
+	return p.SolveContext(context.Background())
+}
+
+// solve problem, aborting the solver when the context is done:
+// maximize the weighted served rate, then minimize cost while keeping the weighted served rate; the objective value is the cost
+func (p *ThroughputProblem) SolveContext(ctx context.Context) error {
+	// setup up problem
+	if err := p.Setup(); err != nil {
+		return err
+	}
+	p.weightedServedRate = 0
+
+	// solve problem with timeout
+	if err := p.solveContext(ctx); err != nil {
+		return err
+	}
+	if err := p.solveCostSecondary(ctx, "throughput", p.throughput(), p.replicaCost()); err != nil {
+		return err
+	}
+
+	// extract (optimal) solution
+	p.setResults(roundReplicas)
+	p.weightedServedRate = 0
+	for i := 0; i < p.numServers; i++ {
+		p.weightedServedRate += p.priority(i) * p.servedRates[i]
+	}
+	return nil
+}
+
+func (p *ThroughputProblem) GetWeightedServedRate() float64 {
+	return p.weightedServedRate
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+// among solutions with the maximum weighted served rate, the cheapest is taken
+func TestThroughputMinimizesCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		limited      bool
+		priorities   []float64
+		numReplicas  [][]int
+		weightedRate float64
+		cost         float64
+	}{
+		{
+			// any number of replicas of either accelerator serves the arrival rates
+			name:        "unlimited",
+			numReplicas: [][]int{{3, 0}, {0, 1}}, weightedRate: 6, cost: 5,
+		},
+		{
+			// four units of the first accelerator only, for the server with the higher priority first
+			name:        "limited",
+			limited:     true,
+			priorities:  []float64{1, 2},
+			numReplicas: [][]int{{1, 0}, {3, 0}}, weightedRate: 7, cost: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := CreateThroughputProblem(2, 2, []float64{1, 2}, [][]int{{1, 1}, {1, 1}},
+				[][]float64{{1, 1}, {1, 3}}, []float64{3, 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.limited {
+				if err := p.SetLimited(2, []int{4, 0}, [][]int{{1, 0}, {0, 1}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.priorities != nil {
+				if err := p.SetPriorities(tt.priorities); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p.SetBackend(NewGoBackend())
+			if err := p.Solve(); err != nil {
+				t.Fatal(err)
+			}
+			if got := p.GetNumReplicas(); !reflect.DeepEqual(got, tt.numReplicas) {
+				t.Errorf("number of replicas = %v, want %v", got, tt.numReplicas)
+			}
+			if got := p.GetWeightedServedRate(); got != tt.weightedRate {
+				t.Errorf("weighted served rate = %v, want %v", got, tt.weightedRate)
+			}
+			if got := p.GetObjectiveValue(); got != tt.cost {
+				t.Errorf("objective value = %v, want cost %v", got, tt.cost)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// collector of every invalid argument of a problem, checked before any model is built
+type validator struct {
+	errs []error
+}
+
+// error holding all problems found, nil if none
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errs: v.errs}
+}
+
+// check that a count is positive
+func (v *validator) positive(arg string, n int) bool {
+	if n <= 0 {
+		v.errs = append(v.errs, &DimensionError{Arg: arg, Index: -1, Msg: fmt.Sprintf("%d, expecting a positive number", n)})
+		return false
+	}
+	return true
+}
+
+// check length of an argument, or of its row at index (if not negative); no expected length if want is not positive
+func (v *validator) length(arg string, index int, n int, want int) bool {
+	if want > 0 && n != want {
+		v.errs = append(v.errs, &DimensionError{Arg: arg, Index: index, Msg: fmt.Sprintf("length %d, expecting %d", n, want)})
+		return false
+	}
+	return true
+}
+
+// check value of element of an argument, given the problem with it if any
+func (v *validator) value(arg string, x float64, problem string, indices ...int) {
+	if problem != "" {
+		v.errs = append(v.errs, &ValueError{Arg: arg, Indices: indices, Value: x, Msg: problem})
+	}
+}
+
+// check a vector argument, element by element
+func checkVector[T int | float64](v *validator, arg string, x []T, want int, check func(k int, x float64) string) {
+	v.length(arg, -1, len(x), want)
+	for k, xk := range x {
+		v.value(arg, float64(xk), check(k, float64(xk)), k)
+	}
+}
+
+// check a matrix argument, row by row and element by element; elements of rows of the wrong length are not checked
+func checkMatrix[T int | float64](v *validator, arg string, x [][]T, wantRows int, wantCols int, check func(i int, j int, x float64) string) {
+	v.length(arg, -1, len(x), wantRows)
+	for i, row := range x {
+		if !v.length(arg, i, len(row), wantCols) {
+			continue
+		}
+		for j, xij := range row {
+			v.value(arg, float64(xij), check(i, j, float64(xij)), i, j)
+		}
+	}
+}
+
+// problem with a value that should be finite and nonnegative, empty if none
+func nonnegative(x float64) string {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, 0):
+		return "expecting a finite number"
+	case x < 0:
+		return "expecting a nonnegative number"
+	}
+	return ""
+}
+
+// validate arguments of a base problem
+func validateProblem(numServers int, numAccelerators int, instanceCost []float64, numInstancesPerReplica [][]int,
+	ratePerReplica [][]float64, arrivalRates []float64) error {
+	v := &validator{}
+	v.positive("numServers", numServers)
+	v.positive("numAccelerators", numAccelerators)
+
+	checkVector(v, "instanceCost", instanceCost, numAccelerators, func(j int, x float64) string {
+		return nonnegative(x)
+	})
+	checkMatrix(v, "ratePerReplica", ratePerReplica, numServers, numAccelerators, func(i int, j int, x float64) string {
+		return nonnegative(x)
+	})
+	checkMatrix(v, "numInstancesPerReplica", numInstancesPerReplica, numServers, numAccelerators, func(i int, j int, x float64) string {
+		if problem := nonnegative(x); problem != "" {
+			return problem
+		}
+		// a pair that may serve load needs accelerator instances
+		if x == 0 && i < len(ratePerReplica) && j < len(ratePerReplica[i]) && ratePerReplica[i][j] > 0 {
+			return "expecting a positive number, as ratePerReplica is positive"
+		}
+		return ""
+	})
+	checkVector(v, "arrivalRates", arrivalRates, numServers, func(i int, x float64) string {
+		return nonnegative(x)
+	})
+	return v.err()
+}
+
+// validate arguments of the limited accelerator units option
+func validateLimited(numAccelerators int, numAcceleratorTypes int, unitsAvail []int, acceleratorTypesMatrix [][]int) error {
+	v := &validator{}
+	v.positive("numAcceleratorTypes", numAcceleratorTypes)
+	checkVector(v, "unitsAvail", unitsAvail, numAcceleratorTypes, func(k int, x float64) string {
+		return nonnegative(x)
+	})
+	checkMatrix(v, "acceleratorTypesMatrix", acceleratorTypesMatrix, numAcceleratorTypes, numAccelerators, func(k int, j int, x float64) string {
+		return nonnegative(x)
+	})
+	return v.err()
+}
+
+// validate arguments of the soft demand option
+func validateSoftDemand(numServers int, unservedPenalty []float64) error {
+	v := &validator{}
+	checkVector(v, "unservedPenalty", unservedPenalty, numServers, func(i int, x float64) string {
+		return nonnegative(x)
+	})
+	return v.err()
+}
+
+// validate weights of servers, e.g. priorities
+func validateWeights(arg string, numServers int, weights []float64) error {
+	v := &validator{}
+	checkVector(v, arg, weights, numServers, func(i int, x float64) string {
+		return nonnegative(x)
+	})
+	return v.err()
+}
+
+// validate bounds on the number of replicas, any of which may be nil
+func validateReplicaBounds(numServers int, numAccelerators int, minReplicas []int, maxReplicas []int, maxPairReplicas [][]int) error {
+	v := &validator{}
+	if minReplicas != nil {
+		checkVector(v, "minReplicas", minReplicas, numServers, func(i int, x float64) string {
+			return nonnegative(x)
+		})
+	}
+	if maxReplicas != nil {
+		checkVector(v, "maxReplicas", maxReplicas, numServers, func(i int, x float64) string {
+			// the most number of replicas of a server is at least its least number
+			if x >= 0 && i < len(minReplicas) && x < float64(minReplicas[i]) {
+				return fmt.Sprintf("expecting at least minReplicas[%d] = %d", i, minReplicas[i])
+			}
+			return ""
+		})
+	}
+	if maxPairReplicas != nil {
+		checkMatrix(v, "maxPairReplicas", maxPairReplicas, numServers, numAccelerators, func(i int, j int, x float64) string {
+			return ""
+		})
+	}
+	return v.err()
+}
+
+// validate most number of accelerator kinds of servers, negative for the default
+func validateMaxKinds(numServers int, serverMaxKinds []int) error {
+	v := &validator{}
+	checkVector(v, "serverMaxKinds", serverMaxKinds, numServers, func(i int, x float64) string {
+		if x == 0 {
+			return "expecting a positive number, or a negative number for the default"
+		}
+		return ""
+	})
+	return v.err()
+}
+
+// validate target utilization of servers
+func validateTargetUtilization(numServers int, targetUtilization []float64) error {
+	v := &validator{}
+	checkVector(v, "targetUtilization", targetUtilization, numServers, func(i int, x float64) string {
+		if !(x > 0 && x <= 1) {
+			return "expecting a number in (0, 1]"
+		}
+		return ""
+	})
+	return v.err()
+}
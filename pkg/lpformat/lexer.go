@@ -0,0 +1,171 @@
+package lpformat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// kind of a lexical token
+type tokenKind int
+
+const (
+	tokEOF       tokenKind = iota
+	tokNumber              // e.g. 3, 2.5, 1e30
+	tokIdent               // variable, constraint, or keyword, e.g. x1, R1, max, int
+	tokOp                  // relational operator: <, <=, =<, >, >=, =>, =
+	tokSign                // + or -
+	tokStar                // * between coefficient and variable
+	tokColon               // after objective sense or constraint name
+	tokSemicolon           // end of statement
+	tokComma               // between declared variables
+)
+
+func (k tokenKind) String() string {
+	return [...]string{"end of file", "number", "identifier", "relational operator", "sign", "'*'", "':'", "';'", "','"}[k]
+}
+
+// lexical token, with position in input
+type token struct {
+	kind tokenKind
+	text string
+	num  float64              // value of number
+	op   model.ConstraintType // type of relational operator
+	line int
+	col  int
+}
+
+// describe token in error messages
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return t.kind.String()
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// split LP text into tokens, skipping white space and comments
+func tokenize(text string) ([]token, error) {
+	src := []rune(text)
+	var tokens []token
+	line, col := 1, 1
+	i := 0
+
+	// advance n runes, tracking position
+	advance := func(n int) {
+		for ; n > 0 && i < len(src); n-- {
+			if src[i] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+			i++
+		}
+	}
+	peek := func(k int) rune {
+		if i+k < len(src) {
+			return src[i+k]
+		}
+		return 0
+	}
+
+	for i < len(src) {
+		c := src[i]
+		startLine, startCol := line, col
+		emit := func(kind tokenKind, n int) token {
+			t := token{kind: kind, text: string(src[i : i+n]), line: startLine, col: startCol}
+			advance(n)
+			return t
+		}
+
+		switch {
+		case unicode.IsSpace(c):
+			advance(1)
+		case c == '/' && peek(1) == '/':
+			for i < len(src) && src[i] != '\n' {
+				advance(1)
+			}
+		case c == '/' && peek(1) == '*':
+			advance(2)
+			for i < len(src) && !(src[i] == '*' && peek(1) == '/') {
+				advance(1)
+			}
+			if i >= len(src) {
+				return nil, &ParseError{Line: startLine, Col: startCol, Msg: "unterminated comment"}
+			}
+			advance(2)
+		case c == '<' || c == '>' || c == '=':
+			n := 1
+			if peek(1) == '=' || (c == '=' && (peek(1) == '<' || peek(1) == '>')) {
+				n = 2
+			}
+			t := emit(tokOp, n)
+			switch {
+			case strings.Contains(t.text, "<"):
+				t.op = model.LE
+			case strings.Contains(t.text, ">"):
+				t.op = model.GE
+			default:
+				t.op = model.EQ
+			}
+			tokens = append(tokens, t)
+		case c == '+' || c == '-':
+			tokens = append(tokens, emit(tokSign, 1))
+		case c == '*':
+			tokens = append(tokens, emit(tokStar, 1))
+		case c == ':':
+			tokens = append(tokens, emit(tokColon, 1))
+		case c == ';':
+			tokens = append(tokens, emit(tokSemicolon, 1))
+		case c == ',':
+			tokens = append(tokens, emit(tokComma, 1))
+		case isDigit(c) || (c == '.' && isDigit(peek(1))):
+			n := 0
+			for isDigit(peek(n)) || peek(n) == '.' {
+				n++
+			}
+			// exponent, unless e starts a variable name as in 3e
+			if e := peek(n); e == 'e' || e == 'E' {
+				if isDigit(peek(n + 1)) {
+					n++
+				} else if (peek(n+1) == '+' || peek(n+1) == '-') && isDigit(peek(n+2)) {
+					n += 2
+				}
+				for isDigit(peek(n)) {
+					n++
+				}
+			}
+			t := emit(tokNumber, n)
+			v, err := strconv.ParseFloat(t.text, 64)
+			if err != nil {
+				return nil, &ParseError{Line: t.line, Col: t.col, Msg: fmt.Sprintf("bad number %q", t.text)}
+			}
+			t.num = v
+			tokens = append(tokens, t)
+		case isIdentStart(c):
+			n := 1
+			for isIdentStart(peek(n)) || isDigit(peek(n)) || strings.ContainsRune(identChars, peek(n)) {
+				n++
+			}
+			tokens = append(tokens, emit(tokIdent, n))
+		default:
+			return nil, &ParseError{Line: line, Col: col, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	tokens = append(tokens, token{kind: tokEOF, line: line, col: col})
+	return tokens, nil
+}
+
+// characters allowed in identifiers after the first one, besides letters and digits
+const identChars = "_[]{}.&#$%~'@^"
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c rune) bool {
+	return c == '_' || unicode.IsLetter(c)
+}
@@ -0,0 +1,438 @@
+// Package lpformat reads models written in the lp_solve LP format
+// (https://lpsolve.sourceforge.net/5.5/lp_format.htm) into solver-neutral models.
+//
+// Supported are the objective function (max:, min:, or none for minimization; a constant term is ignored),
+// named and unnamed constraints with constants on either side, ranges (e.g. -5 <= x + y <= 10),
+// single variable bounds (e.g. x >= 1, -5 <= y <= 5; a named single variable relation is a constraint),
+// and int, bin, and free declarations. Values of 1e30 or more are taken as infinite.
+package lpformat
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// values at least this large are infinite
+const infinity = 1e30
+
+// error in LP text, with position
+type ParseError struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("LP line %d, column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+// parse LP text into a model
+func Parse(r io.Reader) (*model.Model, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenize(string(text))
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		tokens:   tokens,
+		varIndex: make(map[string]int),
+		rowNames: make(map[string]bool),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.build(), nil
+}
+
+// parse LP file into a model
+func ParseFile(fileName string) (*model.Model, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	m, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return m, nil
+}
+
+// linear expression: sum of variable terms and a constant
+type linExpr struct {
+	terms    []model.Entry
+	index    map[int]int // position of variable in terms
+	constant float64
+	hasConst bool
+}
+
+func (e *linExpr) addTerm(col int, val float64) {
+	if k, ok := e.index[col]; ok {
+		e.terms[k].Val += val
+		return
+	}
+	e.index[col] = len(e.terms)
+	e.terms = append(e.terms, model.Entry{Col: col, Val: val})
+}
+
+// nonzero terms
+func (e *linExpr) row() []model.Entry {
+	row := make([]model.Entry, 0, len(e.terms))
+	for _, t := range e.terms {
+		if t.Val != 0 {
+			row = append(row, t)
+		}
+	}
+	return row
+}
+
+// parser state and model being built
+type parser struct {
+	tokens []token
+	pos    int
+
+	vars        []model.Variable
+	varIndex    map[string]int
+	objective   []model.Entry
+	maximize    bool
+	constraints []model.Constraint
+	rowNames    map[string]bool
+}
+
+func (p *parser) peek(k int) token {
+	if p.pos+k < len(p.tokens) {
+		return p.tokens[p.pos+k]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) next() token {
+	t := p.peek(0)
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorAt(t token, format string, args ...any) error {
+	return &ParseError{Line: t.line, Col: t.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorAt(t, "expecting %s, found %s", kind, t)
+	}
+	return t, nil
+}
+
+// column of variable, added to the model when first referenced
+func (p *parser) column(name string) int {
+	if col, ok := p.varIndex[name]; ok {
+		return col
+	}
+	col := len(p.vars)
+	p.vars = append(p.vars, model.Variable{Name: name, Kind: model.Continuous, Lower: 0, Upper: math.Inf(1)})
+	p.varIndex[name] = col
+	return col
+}
+
+func (p *parser) parse() error {
+	if err := p.parseObjective(); err != nil {
+		return err
+	}
+	for p.peek(0).kind != tokEOF {
+		if err := p.parseStatement(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objective function: [max: | min:] expression ;
+func (p *parser) parseObjective() error {
+	if t := p.peek(0); t.kind == tokIdent && p.peek(1).kind == tokColon {
+		switch strings.ToLower(t.text) {
+		case "max", "maximize", "maximise", "maximum":
+			p.maximize = true
+		case "min", "minimize", "minimise", "minimum":
+		default:
+			return p.errorAt(t, "expecting objective function, found %s", t)
+		}
+		p.next()
+		p.next()
+	}
+	if p.peek(0).kind == tokSemicolon {
+		p.next()
+		return nil
+	}
+	e, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	if t := p.peek(0); t.kind == tokOp {
+		return p.errorAt(t, "relational operator in objective function")
+	}
+	if _, err := p.expect(tokSemicolon); err != nil {
+		return err
+	}
+	// a constant only shifts the objective value, and models have no objective offset
+	p.objective = e.terms
+	return nil
+}
+
+// constraint, bound, or declaration
+func (p *parser) parseStatement() error {
+	t := p.peek(0)
+	if t.kind == tokIdent && isSection(t.text) {
+		if k := p.peek(1).kind; k == tokIdent || k == tokSemicolon {
+			return p.parseDeclaration()
+		}
+	}
+
+	var label token
+	if t.kind == tokIdent && p.peek(1).kind == tokColon {
+		label = t
+		if p.rowNames[t.text] {
+			return p.errorAt(t, "duplicate constraint name %s", t.text)
+		}
+		p.next()
+		p.next()
+	}
+
+	// expression, and one or two relational operators each followed by an expression
+	var sides []*linExpr
+	var ops []token
+	e, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	sides = append(sides, e)
+	for p.peek(0).kind == tokOp {
+		ops = append(ops, p.next())
+		e, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		sides = append(sides, e)
+	}
+	if len(ops) == 0 {
+		t := p.peek(0)
+		return p.errorAt(t, "expecting relational operator, found %s", t)
+	}
+	if len(ops) > 2 {
+		return p.errorAt(ops[2], "too many relational operators")
+	}
+	if _, err := p.expect(tokSemicolon); err != nil {
+		return err
+	}
+
+	if len(ops) == 1 {
+		return p.addRelation(label, sides[0], ops[0], sides[1])
+	}
+	return p.addRange(label, sides[0], ops[0], sides[1], ops[1], sides[2])
+}
+
+// lhs op rhs: a bound if unnamed with a single variable on one side and a constant on the other, otherwise a constraint
+func (p *parser) addRelation(label token, lhs *linExpr, op token, rhs *linExpr) error {
+	ct := op.op
+	if len(lhs.terms) == 0 {
+		if len(rhs.terms) == 0 {
+			return p.errorAt(op, "relation without variables")
+		}
+		lhs, rhs = rhs, lhs
+		ct = flip(ct)
+	}
+	if label.kind == tokEOF && len(lhs.terms) == 1 && !lhs.hasConst && len(rhs.terms) == 0 {
+		return p.setBound(op, lhs.terms[0], ct, rhs.constant)
+	}
+
+	row := lhs
+	for _, t := range rhs.terms {
+		row.addTerm(t.Col, -t.Val)
+	}
+	p.addConstraint(label.text, row.row(), ct, rhs.constant-lhs.constant)
+	return nil
+}
+
+// low op expr op high, with both operators <= or both >=
+func (p *parser) addRange(label token, low *linExpr, op1 token, e *linExpr, op2 token, high *linExpr) error {
+	if op1.op != op2.op || op1.op == model.EQ {
+		return p.errorAt(op2, "range operators must both be <= or both be >=")
+	}
+	if len(low.terms) > 0 || len(high.terms) > 0 {
+		return p.errorAt(op1, "range must have constants on both ends")
+	}
+	if len(e.terms) == 0 {
+		return p.errorAt(op1, "range without variables")
+	}
+	lo, hi := low.constant-e.constant, high.constant-e.constant
+	if op1.op == model.GE {
+		lo, hi = hi, lo
+	}
+
+	if label.kind == tokEOF && len(e.terms) == 1 && !e.hasConst {
+		if err := p.setBound(op1, e.terms[0], model.GE, lo); err != nil {
+			return err
+		}
+		return p.setBound(op2, e.terms[0], model.LE, hi)
+	}
+	p.addConstraint(label.text, e.row(), model.GE, lo)
+	upperName := ""
+	if label.text != "" {
+		upperName = label.text + "_upper"
+	}
+	p.addConstraint(upperName, e.row(), model.LE, hi)
+	return nil
+}
+
+// bound of variable given coef*x (ct) value
+func (p *parser) setBound(op token, term model.Entry, ct model.ConstraintType, value float64) error {
+	if term.Val == 0 {
+		return p.errorAt(op, "bound on %s with zero coefficient", p.vars[term.Col].Name)
+	}
+	value = toInfinity(value) / term.Val
+	if term.Val < 0 {
+		ct = flip(ct)
+	}
+	v := &p.vars[term.Col]
+	switch ct {
+	case model.LE:
+		v.Upper = value
+	case model.GE:
+		v.Lower = value
+	case model.EQ:
+		v.Lower, v.Upper = value, value
+	}
+	return nil
+}
+
+func (p *parser) addConstraint(name string, row []model.Entry, ct model.ConstraintType, rhs float64) {
+	if name != "" {
+		p.rowNames[name] = true
+	}
+	p.constraints = append(p.constraints, model.Constraint{Name: name, Row: row, Type: ct, RHS: toInfinity(rhs)})
+}
+
+// section keyword followed by variable names: int x, y;
+func (p *parser) parseDeclaration() error {
+	section := p.next()
+	keyword := strings.ToLower(section.text)
+	if keyword != "int" && keyword != "bin" && keyword != "free" {
+		return p.errorAt(section, "%s section is not supported", section.text)
+	}
+	for {
+		t := p.next()
+		switch t.kind {
+		case tokSemicolon:
+			return nil
+		case tokComma:
+			continue
+		case tokIdent:
+		default:
+			return p.errorAt(t, "expecting variable name in %s section, found %s", keyword, t)
+		}
+		v := &p.vars[p.column(t.text)]
+		switch keyword {
+		case "int":
+			v.Kind = model.Integer
+		case "bin":
+			v.Kind = model.Binary
+			v.Lower, v.Upper = 0, 1
+		case "free":
+			v.Lower = math.Inf(-1)
+		}
+	}
+}
+
+// linear expression: term { sign term }, where term is [signs] [number [*]] [variable]
+func (p *parser) parseExpr() (*linExpr, error) {
+	e := &linExpr{index: make(map[int]int)}
+	for {
+		sign := 1.0
+		for p.peek(0).kind == tokSign {
+			if p.next().text == "-" {
+				sign = -sign
+			}
+		}
+
+		t := p.peek(0)
+		switch t.kind {
+		case tokNumber:
+			p.next()
+			coef := sign * t.num
+			if p.peek(0).kind == tokStar {
+				p.next()
+				if k := p.peek(0); k.kind != tokIdent {
+					return nil, p.errorAt(k, "expecting variable after '*', found %s", k)
+				}
+			}
+			if v := p.peek(0); v.kind == tokIdent {
+				p.next()
+				e.addTerm(p.column(v.text), coef)
+			} else {
+				e.constant += coef
+				e.hasConst = true
+			}
+		case tokIdent:
+			p.next()
+			e.addTerm(p.column(t.text), sign)
+		default:
+			return nil, p.errorAt(t, "expecting number or variable, found %s", t)
+		}
+
+		if p.peek(0).kind != tokSign {
+			return e, nil
+		}
+	}
+}
+
+// model of parsed text
+func (p *parser) build() *model.Model {
+	m := model.NewModel(len(p.vars))
+	copy(m.Vars, p.vars)
+	for _, t := range p.objective {
+		m.Objective[t.Col] = t.Val
+	}
+	if p.maximize {
+		m.SetMaximize()
+	}
+	m.Constraints = p.constraints
+	return m
+}
+
+func isSection(name string) bool {
+	switch strings.ToLower(name) {
+	case "int", "bin", "free", "sec", "sin", "sos", "sos1", "sos2":
+		return true
+	}
+	return false
+}
+
+func flip(ct model.ConstraintType) model.ConstraintType {
+	switch ct {
+	case model.LE:
+		return model.GE
+	case model.GE:
+		return model.LE
+	}
+	return ct
+}
+
+func toInfinity(v float64) float64 {
+	switch {
+	case v >= infinity:
+		return math.Inf(1)
+	case v <= -infinity:
+		return math.Inf(-1)
+	}
+	return v
+}
@@ -0,0 +1,203 @@
+package lpformat
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+func parseString(t *testing.T, text string) *model.Model {
+	t.Helper()
+	m, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", text, err)
+	}
+	return m
+}
+
+func names(m *model.Model) []string {
+	names := make([]string, m.NumVars())
+	for k := range m.Vars {
+		names[k] = m.VarName(k)
+	}
+	return names
+}
+
+func TestParseObjective(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maximize  bool
+		vars      []string
+		objective []float64
+	}{
+		{name: "max", text: "max: 2x + 3y;", maximize: true, vars: []string{"x", "y"}, objective: []float64{2, 3}},
+		{name: "min", text: "min: 2x - 3y;", vars: []string{"x", "y"}, objective: []float64{2, -3}},
+		{name: "long keyword", text: "Maximise: x;", maximize: true, vars: []string{"x"}, objective: []float64{1}},
+		{name: "no keyword", text: "3 x1 + 2 x2;", vars: []string{"x1", "x2"}, objective: []float64{3, 2}},
+		{name: "star", text: "max: 3 * x - -2 y;", maximize: true, vars: []string{"x", "y"}, objective: []float64{3, 2}},
+		{name: "repeated variable", text: "max: 2 x + y + 3 x;", maximize: true, vars: []string{"x", "y"}, objective: []float64{5, 1}},
+		{name: "comments", text: "/* objective */ max: x; // end", maximize: true, vars: []string{"x"}, objective: []float64{1}},
+		{name: "empty", text: "max: ;", maximize: true, vars: []string{}, objective: []float64{}},
+		{name: "constant only", text: "max: 0;", maximize: true, vars: []string{}, objective: []float64{}},
+		{name: "constant ignored", text: "min: 3 + x;", vars: []string{"x"}, objective: []float64{1}},
+		{name: "constants ignored", text: "max: 2 x + 4 - 1;", maximize: true, vars: []string{"x"}, objective: []float64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parseString(t, tt.text)
+			if m.Maximize != tt.maximize {
+				t.Errorf("Maximize = %v, want %v", m.Maximize, tt.maximize)
+			}
+			if got := names(m); !reflect.DeepEqual(got, tt.vars) {
+				t.Errorf("vars = %v, want %v", got, tt.vars)
+			}
+			if !reflect.DeepEqual(m.Objective, tt.objective) {
+				t.Errorf("objective = %v, want %v", m.Objective, tt.objective)
+			}
+		})
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name        string
+		text        string
+		vars        []model.Variable
+		constraints []model.Constraint
+	}{
+		{
+			name: "constraints",
+			text: "max: x + y;\nc1: x + y <= 4;\n3 x - y >= 2;\nc3: 2 x + 3 y >= 2 x + 6;\n-4 <= x - 2;\n",
+			vars: []model.Variable{
+				{Name: "x", Kind: model.Continuous, Lower: 0, Upper: inf},
+				{Name: "y", Kind: model.Continuous, Lower: 0, Upper: inf},
+			},
+			constraints: []model.Constraint{
+				{Name: "c1", Row: []model.Entry{{Col: 0, Val: 1}, {Col: 1, Val: 1}}, Type: model.LE, RHS: 4},
+				{Row: []model.Entry{{Col: 0, Val: 3}, {Col: 1, Val: -1}}, Type: model.GE, RHS: 2},
+				{Name: "c3", Row: []model.Entry{{Col: 1, Val: 3}}, Type: model.GE, RHS: 6},
+				{Row: []model.Entry{{Col: 0, Val: 1}}, Type: model.GE, RHS: -2},
+			},
+		},
+		{
+			name: "bounds",
+			text: "min: ;\nx >= 1;\n-y >= -4;\n3 z <= 12;\n-5 <= w <= 5;\nv = 2;\nu >= -1e30;\nu <= 1e30;\n",
+			vars: []model.Variable{
+				{Name: "x", Kind: model.Continuous, Lower: 1, Upper: inf},
+				{Name: "y", Kind: model.Continuous, Lower: 0, Upper: 4},
+				{Name: "z", Kind: model.Continuous, Lower: 0, Upper: 4},
+				{Name: "w", Kind: model.Continuous, Lower: -5, Upper: 5},
+				{Name: "v", Kind: model.Continuous, Lower: 2, Upper: 2},
+				{Name: "u", Kind: model.Continuous, Lower: math.Inf(-1), Upper: inf},
+			},
+		},
+		{
+			name: "named single variable constraint",
+			text: "min: ;\nR1: x >= 1;\nR2: 3 <= y;\n",
+			vars: []model.Variable{
+				{Name: "x", Kind: model.Continuous, Lower: 0, Upper: inf},
+				{Name: "y", Kind: model.Continuous, Lower: 0, Upper: inf},
+			},
+			constraints: []model.Constraint{
+				{Name: "R1", Row: []model.Entry{{Col: 0, Val: 1}}, Type: model.GE, RHS: 1},
+				{Name: "R2", Row: []model.Entry{{Col: 1, Val: 1}}, Type: model.GE, RHS: 3},
+			},
+		},
+		{
+			name: "ranges",
+			text: "min: ;\nR1: -5 <= x + y <= 10;\n8 >= x - y >= 2;\nR3: 1 <= x <= 3;\n",
+			vars: []model.Variable{
+				{Name: "x", Kind: model.Continuous, Lower: 0, Upper: inf},
+				{Name: "y", Kind: model.Continuous, Lower: 0, Upper: inf},
+			},
+			constraints: []model.Constraint{
+				{Name: "R1", Row: []model.Entry{{Col: 0, Val: 1}, {Col: 1, Val: 1}}, Type: model.GE, RHS: -5},
+				{Name: "R1_upper", Row: []model.Entry{{Col: 0, Val: 1}, {Col: 1, Val: 1}}, Type: model.LE, RHS: 10},
+				{Row: []model.Entry{{Col: 0, Val: 1}, {Col: 1, Val: -1}}, Type: model.GE, RHS: 2},
+				{Row: []model.Entry{{Col: 0, Val: 1}, {Col: 1, Val: -1}}, Type: model.LE, RHS: 8},
+				{Name: "R3", Row: []model.Entry{{Col: 0, Val: 1}}, Type: model.GE, RHS: 1},
+				{Name: "R3_upper", Row: []model.Entry{{Col: 0, Val: 1}}, Type: model.LE, RHS: 3},
+			},
+		},
+		{
+			name: "declarations",
+			text: "max: x + b + f;\nx <= 10;\nint x, n;\nbin b;\nfree f;\n",
+			vars: []model.Variable{
+				{Name: "x", Kind: model.Integer, Lower: 0, Upper: 10},
+				{Name: "b", Kind: model.Binary, Lower: 0, Upper: 1},
+				{Name: "f", Kind: model.Continuous, Lower: math.Inf(-1), Upper: inf},
+				{Name: "n", Kind: model.Integer, Lower: 0, Upper: inf},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parseString(t, tt.text)
+			if !reflect.DeepEqual(m.Vars, tt.vars) {
+				t.Errorf("vars = %v, want %v", m.Vars, tt.vars)
+			}
+			if len(m.Constraints) != len(tt.constraints) {
+				t.Fatalf("constraints = %v, want %v", m.Constraints, tt.constraints)
+			}
+			for r, want := range tt.constraints {
+				if got := m.Constraints[r]; !reflect.DeepEqual(got, want) {
+					t.Errorf("constraint %d = %v, want %v", r, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  string
+	}{
+		{name: "bad objective keyword", text: "maxx: x;", err: `LP line 1, column 1: expecting objective function, found "maxx"`},
+		{name: "relation in objective", text: "max: x >= 1;", err: `LP line 1, column 8: relational operator in objective function`},
+		{name: "missing operator", text: "max: x;\nc1: x + y;", err: `LP line 2, column 10: expecting relational operator, found ";"`},
+		{name: "missing semicolon", text: "max: x;\nc1: x <= 4", err: `LP line 2, column 11: expecting ';', found end of file`},
+		{name: "unexpected character", text: "max: x;\nc1: x ? 3;", err: `LP line 2, column 7: unexpected character '?'`},
+		{name: "unterminated comment", text: "max: x;\n  /* no end", err: `LP line 2, column 3: unterminated comment`},
+		{name: "duplicate name", text: "max: x;\nc1: x >= 1;\nc1: x <= 2;", err: `LP line 3, column 1: duplicate constraint name c1`},
+		{name: "star without variable", text: "min: 2 * 3;", err: `LP line 1, column 10: expecting variable after '*', found "3"`},
+		{name: "mixed range", text: "min: ;\n3 <= x + y >= 1;", err: `LP line 2, column 12: range operators must both be <= or both be >=`},
+		{name: "range without constants", text: "min: ;\nx <= y <= 3;", err: `LP line 2, column 3: range must have constants on both ends`},
+		{name: "too many operators", text: "min: ;\n1 <= x <= 2 <= 3;", err: `LP line 2, column 13: too many relational operators`},
+		{name: "no variables", text: "min: ;\n3 >= 2;", err: `LP line 2, column 3: relation without variables`},
+		{name: "zero coefficient bound", text: "min: ;\n0 x >= 2;", err: `LP line 2, column 5: bound on x with zero coefficient`},
+		{name: "sec section", text: "min: x;\nx <= 5;\nsec x;", err: `LP line 3, column 1: sec section is not supported`},
+		{name: "bad declaration", text: "min: x;\nint x 3;", err: `LP line 2, column 7: expecting variable name in int section, found "3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.text))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse() error = %v, want *ParseError", err)
+			}
+			if err.Error() != tt.err {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestParseFixtures(t *testing.T) {
+	for _, fileName := range []string{"../../demos/cmd/test-lp.lp", "../../demos/cmd/test-mip.lp"} {
+		m, err := ParseFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.NumVars() != 2 || m.NumConstraints() == 0 {
+			t.Errorf("%s: %d variables and %d constraints", fileName, m.NumVars(), m.NumConstraints())
+		}
+	}
+}
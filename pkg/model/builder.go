@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"math"
+)
+
+// variable of a model, given by its column
+type Var int
+
+// add a named variable of the given kind: nonnegative, and at most one if binary
+func (m *Model) AddVar(name string, kind VarKind) Var {
+	upper := math.Inf(1)
+	if kind == Binary {
+		upper = 1
+	}
+	return m.AddBoundedVar(name, kind, 0, upper)
+}
+
+// add a named variable of the given kind with bounds
+func (m *Model) AddBoundedVar(name string, kind VarKind, lower float64, upper float64) Var {
+	m.Vars = append(m.Vars, Variable{Name: name, Kind: kind, Lower: lower, Upper: upper})
+	m.Objective = append(m.Objective, 0)
+	return Var(len(m.Vars) - 1)
+}
+
+// variable with the given name, if any
+func (m *Model) VarByName(name string) (Var, bool) {
+	for k, v := range m.Vars {
+		if v.Name == name {
+			return Var(k), true
+		}
+	}
+	return -1, false
+}
+
+// Linear expression: sum of terms, coefficient times variable
+type Expr struct {
+	terms []Entry
+}
+
+func NewExpr() *Expr {
+	return &Expr{terms: make([]Entry, 0)}
+}
+
+// add term coef*v, returning the expression
+func (e *Expr) Add(coef float64, v Var) *Expr {
+	e.terms = append(e.terms, Entry{Col: int(v), Val: coef})
+	return e
+}
+
+// add coef times another expression, returning the expression
+func (e *Expr) AddExpr(coef float64, f *Expr) *Expr {
+	for _, t := range f.terms {
+		e.terms = append(e.terms, Entry{Col: t.Col, Val: coef * t.Val})
+	}
+	return e
+}
+
+// number of terms, before combining terms of the same variable
+func (e *Expr) Len() int {
+	return len(e.terms)
+}
+
+// nonzero coefficients, combining terms of the same variable
+func (e *Expr) Entries() []Entry {
+	entries := make([]Entry, 0, len(e.terms))
+	if isIncreasing(e.terms) {
+		// no terms to combine, as when built by looping over variables in order
+		for _, t := range e.terms {
+			if t.Val != 0 {
+				entries = append(entries, t)
+			}
+		}
+		return entries
+	}
+	index := make(map[int]int, len(e.terms)) // position of variable in entries
+	for _, t := range e.terms {
+		if k, ok := index[t.Col]; ok {
+			entries[k].Val += t.Val
+			continue
+		}
+		index[t.Col] = len(entries)
+		entries = append(entries, t)
+	}
+	nonzero := entries[:0]
+	for _, t := range entries {
+		if t.Val != 0 {
+			nonzero = append(nonzero, t)
+		}
+	}
+	return nonzero
+}
+
+// check if columns of terms are strictly increasing
+func isIncreasing(terms []Entry) bool {
+	for k := 1; k < len(terms); k++ {
+		if terms[k].Col <= terms[k-1].Col {
+			return false
+		}
+	}
+	return true
+}
+
+// add a named constraint: e (ct) rightHand
+func (m *Model) AddConstr(name string, e *Expr, ct ConstraintType, rightHand float64) error {
+	if err := m.checkExpr(e); err != nil {
+		return err
+	}
+	m.Constraints = append(m.Constraints, Constraint{Name: name, Row: e.Entries(), Type: ct, RHS: rightHand})
+	return nil
+}
+
+// set objective function (minimized unless SetMaximize is called)
+func (m *Model) SetObjective(e *Expr) error {
+	if err := m.checkExpr(e); err != nil {
+		return err
+	}
+	for k := range m.Objective {
+		m.Objective[k] = 0
+	}
+	for _, t := range e.Entries() {
+		m.Objective[t.Col] = t.Val
+	}
+	return nil
+}
+
+func (m *Model) checkExpr(e *Expr) error {
+	for _, t := range e.terms {
+		if t.Col < 0 || t.Col >= len(m.Vars) {
+			return errors.New("expression variable out of range")
+		}
+	}
+	return nil
+}
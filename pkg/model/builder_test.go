@@ -0,0 +1,116 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExprEntries(t *testing.T) {
+	m := NewModel(0)
+	x := m.AddVar("x", Continuous)
+	y := m.AddVar("y", Integer)
+	z := m.AddVar("z", Binary)
+	tests := []struct {
+		name    string
+		expr    *Expr
+		length  int
+		entries []Entry
+	}{
+		{name: "empty", expr: NewExpr(), entries: []Entry{}},
+		{name: "increasing", expr: NewExpr().Add(1, x).Add(0, y).Add(3, z), length: 3,
+			entries: []Entry{{Col: 0, Val: 1}, {Col: 2, Val: 3}}},
+		{name: "duplicates merged in order of first term", expr: NewExpr().Add(2, y).Add(1, x).Add(3, y), length: 3,
+			entries: []Entry{{Col: 1, Val: 5}, {Col: 0, Val: 1}}},
+		{name: "duplicates canceled", expr: NewExpr().Add(1, x).Add(2, z).Add(-1, x), length: 3,
+			entries: []Entry{{Col: 2, Val: 2}}},
+		{name: "scaled expression", expr: NewExpr().Add(1, x).AddExpr(-2, NewExpr().Add(1, x).Add(1, y)), length: 3,
+			entries: []Entry{{Col: 0, Val: -1}, {Col: 1, Val: -2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expr.Len(); got != tt.length {
+				t.Errorf("Len() = %d, want %d", got, tt.length)
+			}
+			if got := tt.expr.Entries(); !reflect.DeepEqual(got, tt.entries) {
+				t.Errorf("Entries() = %v, want %v", got, tt.entries)
+			}
+		})
+	}
+}
+
+func TestAddConstrMergesTerms(t *testing.T) {
+	m := NewModel(0)
+	x := m.AddVar("x", Continuous)
+	y := m.AddVar("y", Continuous)
+	if err := m.AddConstr("c", NewExpr().Add(1, y).Add(2, x).Add(1, y), LE, 4); err != nil {
+		t.Fatal(err)
+	}
+	want := Constraint{Name: "c", Row: []Entry{{Col: 1, Val: 2}, {Col: 0, Val: 2}}, Type: LE, RHS: 4}
+	if got := m.Constraints[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("constraint = %v, want %v", got, want)
+	}
+	if err := m.SetObjective(NewExpr().Add(1, x).Add(-1, y).Add(3, x)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{4, -1}; !reflect.DeepEqual(m.Objective, want) {
+		t.Errorf("objective = %v, want %v", m.Objective, want)
+	}
+}
+
+// variables of another model, or out of range, are rejected without changing the model
+func TestCheckExpr(t *testing.T) {
+	m := NewModel(0)
+	x := m.AddVar("x", Continuous)
+	other := NewModel(0)
+	other.AddVar("a", Continuous)
+	other.AddVar("b", Continuous)
+	foreign := other.AddVar("c", Continuous)
+
+	tests := []struct {
+		name string
+		expr *Expr
+		ok   bool
+	}{
+		{name: "own variable", expr: NewExpr().Add(1, x), ok: true},
+		{name: "empty", expr: NewExpr(), ok: true},
+		{name: "foreign variable", expr: NewExpr().Add(1, x).Add(1, foreign)},
+		{name: "negative", expr: NewExpr().Add(1, Var(-1))},
+		{name: "past last", expr: NewExpr().Add(1, Var(m.NumVars()))},
+		{name: "in added expression", expr: NewExpr().AddExpr(2, NewExpr().Add(1, foreign))},
+	}
+	want := m.Copy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.checkExpr(tt.expr); (err == nil) != tt.ok {
+				t.Errorf("checkExpr() error = %v, want ok %v", err, tt.ok)
+			}
+			c := m.Copy()
+			errConstr := c.AddConstr("c", tt.expr, GE, 1)
+			errObj := c.SetObjective(tt.expr)
+			if tt.ok {
+				return
+			}
+			if errConstr == nil || errObj == nil {
+				t.Errorf("AddConstr() error = %v, SetObjective() error = %v, want errors", errConstr, errObj)
+			}
+			if !reflect.DeepEqual(c, want) {
+				t.Errorf("model changed to %v", c)
+			}
+		})
+	}
+}
+
+func TestVarByName(t *testing.T) {
+	m := NewModel(0)
+	m.AddVar("x", Continuous)
+	y := m.AddVar("y", Binary)
+	if v, ok := m.VarByName("y"); !ok || v != y {
+		t.Errorf("VarByName(y) = %d, %v, want %d, true", v, ok, y)
+	}
+	if _, ok := m.VarByName("z"); ok {
+		t.Error("VarByName(z) found")
+	}
+	if got := m.Vars[y]; got.Lower != 0 || got.Upper != 1 {
+		t.Errorf("binary bounds = [%v, %v], want [0, 1]", got.Lower, got.Upper)
+	}
+}
@@ -0,0 +1,188 @@
+// Package model defines the solver-neutral MILP model solved by the solver backends,
+// with a builder of named variables, linear expressions, and named constraints.
+package model
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// kind of a model variable
+type VarKind int
+
+const (
+	Continuous VarKind = iota // real valued variable
+	Integer                   // integer valued variable
+	Binary                    // variable with value 0 or 1
+)
+
+func (vk VarKind) String() string {
+	return [...]string{"Continuous", "Integer", "Binary"}[vk]
+}
+
+// type of a model constraint
+type ConstraintType int
+
+const (
+	LE ConstraintType = iota + 1 // less than or equal
+	GE                           // greater than or equal
+	EQ                           // equal
+)
+
+func (ct ConstraintType) String() string {
+	return [...]string{"", "<=", ">=", "="}[ct]
+}
+
+// nonzero coefficient of a variable in a linear expression
+type Entry struct {
+	Col int
+	Val float64
+}
+
+// variable of a model
+type Variable struct {
+	Name  string
+	Kind  VarKind
+	Lower float64 // lower bound
+	Upper float64 // upper bound, math.Inf(1) if unbounded
+}
+
+// linear constraint of a model: Row (Type) RHS
+type Constraint struct {
+	Name string
+	Row  []Entry // nonzero coefficients
+	Type ConstraintType
+	RHS  float64
+}
+
+// Solver-neutral MILP model, solved by a Backend
+type Model struct {
+	Vars        []Variable
+	Objective   []float64 // objective coefficients [len(Vars)]
+	Maximize    bool
+	Constraints []Constraint
+}
+
+// create a model with nonnegative continuous variables
+func NewModel(numVars int) *Model {
+	m := &Model{
+		Vars:      make([]Variable, numVars),
+		Objective: make([]float64, numVars),
+	}
+	for k := range m.Vars {
+		m.Vars[k] = Variable{Kind: Continuous, Lower: 0, Upper: math.Inf(1)}
+	}
+	return m
+}
+
+func (m *Model) NumVars() int {
+	return len(m.Vars)
+}
+
+func (m *Model) NumConstraints() int {
+	return len(m.Constraints)
+}
+
+func (m *Model) SetColName(col int, name string) {
+	m.Vars[col].Name = name
+}
+
+// name of variable, x<col> if not set
+func (m *Model) VarName(col int) string {
+	if name := m.Vars[col].Name; name != "" {
+		return name
+	}
+	return "x" + strconv.Itoa(col)
+}
+
+func (m *Model) SetRowName(row int, name string) {
+	m.Constraints[row].Name = name
+}
+
+// name of constraint, c<row> if not set
+func (m *Model) ConstraintName(row int) string {
+	if name := m.Constraints[row].Name; name != "" {
+		return name
+	}
+	return "c" + strconv.Itoa(row)
+}
+
+func (m *Model) SetInt(col int, mustBeInt bool) {
+	if mustBeInt {
+		m.Vars[col].Kind = Integer
+	} else {
+		m.Vars[col].Kind = Continuous
+	}
+}
+
+// binary variables are bounded to [0,1]
+func (m *Model) SetBinary(col int, mustBeBinary bool) {
+	if mustBeBinary {
+		m.Vars[col].Kind = Binary
+		m.Vars[col].Lower = 0
+		m.Vars[col].Upper = 1
+	} else {
+		m.Vars[col].Kind = Continuous
+	}
+}
+
+func (m *Model) SetBounds(col int, lower float64, upper float64) {
+	m.Vars[col].Lower = lower
+	m.Vars[col].Upper = upper
+}
+
+// set objective function coefficients (minimized unless SetMaximize is called)
+func (m *Model) SetObjFn(row []float64) {
+	copy(m.Objective, row)
+}
+
+func (m *Model) SetMaximize() {
+	m.Maximize = true
+}
+
+// add a constraint given the coefficients of all variables
+func (m *Model) AddConstraint(row []float64, ct ConstraintType, rightHand float64) error {
+	if len(row) != len(m.Vars) {
+		return errors.New("inconsistent constraint size")
+	}
+	entries := make([]Entry, 0)
+	for k, v := range row {
+		if v != 0 {
+			entries = append(entries, Entry{Col: k, Val: v})
+		}
+	}
+	m.Constraints = append(m.Constraints, Constraint{Row: entries, Type: ct, RHS: rightHand})
+	return nil
+}
+
+// add a constraint given the nonzero coefficients
+func (m *Model) AddConstraintSparse(row []Entry, ct ConstraintType, rightHand float64) error {
+	for _, e := range row {
+		if e.Col < 0 || e.Col >= len(m.Vars) {
+			return errors.New("constraint column out of range")
+		}
+	}
+	entries := make([]Entry, len(row))
+	copy(entries, row)
+	m.Constraints = append(m.Constraints, Constraint{Row: entries, Type: ct, RHS: rightHand})
+	return nil
+}
+
+// deep copy of the model, to be changed without changing the model
+func (m *Model) Copy() *Model {
+	c := &Model{
+		Vars:        make([]Variable, len(m.Vars)),
+		Objective:   make([]float64, len(m.Objective)),
+		Maximize:    m.Maximize,
+		Constraints: make([]Constraint, len(m.Constraints)),
+	}
+	copy(c.Vars, m.Vars)
+	copy(c.Objective, m.Objective)
+	for r, con := range m.Constraints {
+		c.Constraints[r] = con
+		c.Constraints[r].Row = make([]Entry, len(con.Row))
+		copy(c.Constraints[r].Row, con.Row)
+	}
+	return c
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+// changes to the copy leave the model unchanged
+func TestCopy(t *testing.T) {
+	m := NewModel(0)
+	x := m.AddVar("x", Integer)
+	y := m.AddBoundedVar("y", Continuous, 1, 5)
+	m.SetObjective(NewExpr().Add(1, x).Add(2, y))
+	m.AddConstr("c1", NewExpr().Add(1, x).Add(1, y), LE, 10)
+
+	want := &Model{
+		Vars:        append([]Variable{}, m.Vars...),
+		Objective:   append([]float64{}, m.Objective...),
+		Constraints: []Constraint{{Name: "c1", Row: []Entry{{Col: 0, Val: 1}, {Col: 1, Val: 1}}, Type: LE, RHS: 10}},
+	}
+	c := m.Copy()
+	if !reflect.DeepEqual(c, m) {
+		t.Fatalf("Copy() = %v, want %v", c, m)
+	}
+
+	c.SetMaximize()
+	c.SetBounds(int(x), 2, 3)
+	c.SetInt(int(x), false)
+	c.Objective[1] = 7
+	c.Constraints[0].Row[1].Val = 4
+	c.Constraints[0].RHS = 20
+	c.SetRowName(0, "renamed")
+	c.AddVar("z", Binary)
+	c.AddConstr("c2", NewExpr().Add(1, y), GE, 2)
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("model = %v after changing copy, want %v", m, want)
+	}
+}
+
+func TestAddConstraint(t *testing.T) {
+	m := NewModel(3)
+	if err := m.AddConstraint([]float64{1, 0, 2}, GE, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddConstraint([]float64{1, 2}, GE, 1); err == nil {
+		t.Error("AddConstraint() of short row: no error")
+	}
+	if err := m.AddConstraintSparse([]Entry{{Col: 3, Val: 1}}, LE, 1); err == nil {
+		t.Error("AddConstraintSparse() of column out of range: no error")
+	}
+	want := []Constraint{{Row: []Entry{{Col: 0, Val: 1}, {Col: 2, Val: 2}}, Type: GE, RHS: 1}}
+	if !reflect.DeepEqual(m.Constraints, want) {
+		t.Errorf("constraints = %v, want %v", m.Constraints, want)
+	}
+	if got := m.ConstraintName(0); got != "c0" {
+		t.Errorf("ConstraintName(0) = %q, want c0", got)
+	}
+	if got := m.VarName(1); got != "x1" {
+		t.Errorf("VarName(1) = %q, want x1", got)
+	}
+}
@@ -0,0 +1,195 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// write model in lp_solve LP format
+func (m *Model) WriteLP(w io.Writer) error {
+	b := bufio.NewWriter(w)
+
+	// objective function
+	b.WriteString("/* Objective function */\n")
+	if m.Maximize {
+		b.WriteString("max:")
+	} else {
+		b.WriteString("min:")
+	}
+	for k, c := range m.Objective {
+		if c != 0 {
+			fmt.Fprintf(b, " %s %s", lpCoefficient(c), m.VarName(k))
+		}
+	}
+	b.WriteString(";\n")
+
+	// constraints, all named so that single variable constraints are not taken as bounds
+	b.WriteString("\n/* Constraints */\n")
+	for r, c := range m.Constraints {
+		fmt.Fprintf(b, "%s:", m.ConstraintName(r))
+		if len(c.Row) == 0 && m.NumVars() > 0 {
+			fmt.Fprintf(b, " 0 %s", m.VarName(0))
+		}
+		for _, e := range c.Row {
+			fmt.Fprintf(b, " %s %s", lpCoefficient(e.Val), m.VarName(e.Col))
+		}
+		fmt.Fprintf(b, " %s %s;\n", c.Type, formatFloat(c.RHS))
+	}
+
+	// bounds, other than the default [0, +inf) and those of binary variables
+	var free, ints, bins []string
+	wroteHeader := false
+	for k, v := range m.Vars {
+		switch {
+		case v.Kind == Binary:
+			bins = append(bins, m.VarName(k))
+			continue
+		case v.Kind == Integer:
+			ints = append(ints, m.VarName(k))
+		}
+		if math.IsInf(v.Lower, -1) && math.IsInf(v.Upper, 1) {
+			free = append(free, m.VarName(k))
+			continue
+		}
+		if v.Lower == 0 && math.IsInf(v.Upper, 1) {
+			continue
+		}
+		if !wroteHeader {
+			b.WriteString("\n/* Bounds */\n")
+			wroteHeader = true
+		}
+		switch {
+		case v.Lower == v.Upper:
+			fmt.Fprintf(b, "%s = %s;\n", m.VarName(k), formatFloat(v.Lower))
+		case math.IsInf(v.Upper, 1):
+			fmt.Fprintf(b, "%s >= %s;\n", m.VarName(k), formatFloat(v.Lower))
+		case math.IsInf(v.Lower, -1):
+			fmt.Fprintf(b, "%s >= -1e30;\n%s <= %s;\n", m.VarName(k), m.VarName(k), formatFloat(v.Upper))
+		default:
+			fmt.Fprintf(b, "%s <= %s <= %s;\n", formatFloat(v.Lower), m.VarName(k), formatFloat(v.Upper))
+		}
+	}
+
+	// declarations
+	if len(free)+len(ints)+len(bins) > 0 {
+		b.WriteString("\n/* Declarations */\n")
+	}
+	for _, decl := range []struct {
+		keyword string
+		names   []string
+	}{{"free", free}, {"int", ints}, {"bin", bins}} {
+		if len(decl.names) > 0 {
+			fmt.Fprintf(b, "%s %s;\n", decl.keyword, strings.Join(decl.names, ","))
+		}
+	}
+	return b.Flush()
+}
+
+// write model in free MPS format
+func (m *Model) WriteMPS(w io.Writer) error {
+	const objName = "obj"
+	b := bufio.NewWriter(w)
+
+	b.WriteString("NAME model\n")
+	if m.Maximize {
+		b.WriteString("OBJSENSE\n    MAX\n")
+	}
+
+	// rows
+	b.WriteString("ROWS\n")
+	fmt.Fprintf(b, " N  %s\n", objName)
+	for r, c := range m.Constraints {
+		fmt.Fprintf(b, " %s  %s\n", map[ConstraintType]string{LE: "L", GE: "G", EQ: "E"}[c.Type], m.ConstraintName(r))
+	}
+
+	// columns, with integer variables between markers
+	cols := make([][]Entry, m.NumVars()) // nonzero coefficients by column, Col holding the row index
+	for r, c := range m.Constraints {
+		for _, e := range c.Row {
+			cols[e.Col] = append(cols[e.Col], Entry{Col: r, Val: e.Val})
+		}
+	}
+	b.WriteString("COLUMNS\n")
+	inInt := false
+	numMarkers := 0
+	for k, v := range m.Vars {
+		isInt := v.Kind != Continuous
+		if isInt != inInt {
+			marker := "'INTORG'"
+			if !isInt {
+				marker = "'INTEND'"
+			}
+			fmt.Fprintf(b, "    MARKER%d  'MARKER'  %s\n", numMarkers, marker)
+			numMarkers++
+			inInt = isInt
+		}
+		name := m.VarName(k)
+		wrote := false
+		if c := m.Objective[k]; c != 0 {
+			fmt.Fprintf(b, "    %s  %s  %s\n", name, objName, formatFloat(c))
+			wrote = true
+		}
+		for _, e := range cols[k] {
+			fmt.Fprintf(b, "    %s  %s  %s\n", name, m.ConstraintName(e.Col), formatFloat(e.Val))
+			wrote = true
+		}
+		if !wrote {
+			// declare column without coefficients
+			fmt.Fprintf(b, "    %s  %s  0\n", name, objName)
+		}
+	}
+	if inInt {
+		fmt.Fprintf(b, "    MARKER%d  'MARKER'  'INTEND'\n", numMarkers)
+	}
+
+	// right hand sides
+	b.WriteString("RHS\n")
+	for r, c := range m.Constraints {
+		if c.RHS != 0 {
+			fmt.Fprintf(b, "    RHS  %s  %s\n", m.ConstraintName(r), formatFloat(c.RHS))
+		}
+	}
+
+	// bounds, explicit for integer variables since their default upper bound varies among solvers
+	b.WriteString("BOUNDS\n")
+	for k, v := range m.Vars {
+		name := m.VarName(k)
+		switch {
+		case v.Kind == Binary:
+			fmt.Fprintf(b, " BV BND  %s\n", name)
+		case math.IsInf(v.Lower, -1) && math.IsInf(v.Upper, 1):
+			fmt.Fprintf(b, " FR BND  %s\n", name)
+		case v.Lower == v.Upper:
+			fmt.Fprintf(b, " FX BND  %s  %s\n", name, formatFloat(v.Lower))
+		default:
+			if math.IsInf(v.Lower, -1) {
+				fmt.Fprintf(b, " MI BND  %s\n", name)
+			} else if v.Lower != 0 {
+				fmt.Fprintf(b, " LO BND  %s  %s\n", name, formatFloat(v.Lower))
+			}
+			if !math.IsInf(v.Upper, 1) {
+				fmt.Fprintf(b, " UP BND  %s  %s\n", name, formatFloat(v.Upper))
+			} else if v.Kind == Integer {
+				fmt.Fprintf(b, " PL BND  %s\n", name)
+			}
+		}
+	}
+	b.WriteString("ENDATA\n")
+	return b.Flush()
+}
+
+// signed coefficient: +c or -c
+func lpCoefficient(c float64) string {
+	if c < 0 {
+		return formatFloat(c)
+	}
+	return "+" + formatFloat(c)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
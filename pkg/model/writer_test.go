@@ -0,0 +1,138 @@
+package model_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/llm-inferno/lpsolve/pkg/lpformat"
+	"github.com/llm-inferno/lpsolve/pkg/model"
+)
+
+// model with variables of all kinds and bounds, and an unnamed and an empty constraint
+func writerTestModel() *model.Model {
+	inf := math.Inf(1)
+	m := model.NewModel(0)
+	x := m.AddVar("x", model.Continuous)
+	y := m.AddVar("y", model.Integer)
+	z := m.AddBoundedVar("z", model.Integer, 1, 5)
+	b := m.AddVar("b", model.Binary)
+	f := m.AddBoundedVar("f", model.Continuous, -inf, inf)
+	u := m.AddBoundedVar("u", model.Continuous, -inf, 8)
+	v := m.AddBoundedVar("v", model.Continuous, 2.5, inf)
+	w := m.AddBoundedVar("w", model.Continuous, 3, 3)
+	m.SetObjective(model.NewExpr().Add(2, x).Add(3, y).Add(-1, z).Add(1, b))
+	m.SetMaximize()
+	m.AddConstr("cap", model.NewExpr().Add(1, x).Add(1, y).Add(1, f).Add(1, u), model.LE, 10)
+	m.AddConstr("link", model.NewExpr().Add(1, y).Add(-1, z).Add(0.5, v), model.GE, -1)
+	m.AddConstr("", model.NewExpr().Add(1, x).Add(1, w), model.EQ, 0)
+	m.AddConstr("empty", model.NewExpr(), model.GE, 0)
+	return m
+}
+
+func TestWriteLP(t *testing.T) {
+	want := `/* Objective function */
+max: +2 x +3 y -1 z +1 b;
+
+/* Constraints */
+cap: +1 x +1 y +1 f +1 u <= 10;
+link: +1 y -1 z +0.5 v >= -1;
+c2: +1 x +1 w = 0;
+empty: 0 x >= 0;
+
+/* Bounds */
+1 <= z <= 5;
+u >= -1e30;
+u <= 8;
+v >= 2.5;
+w = 3;
+
+/* Declarations */
+free f;
+int y,z;
+bin b;
+`
+	var buf bytes.Buffer
+	if err := writerTestModel().WriteLP(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("WriteLP() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteMPS(t *testing.T) {
+	want := `NAME model
+OBJSENSE
+    MAX
+ROWS
+ N  obj
+ L  cap
+ G  link
+ E  c2
+ G  empty
+COLUMNS
+    x  obj  2
+    x  cap  1
+    x  c2  1
+    MARKER0  'MARKER'  'INTORG'
+    y  obj  3
+    y  cap  1
+    y  link  1
+    z  obj  -1
+    z  link  -1
+    b  obj  1
+    MARKER1  'MARKER'  'INTEND'
+    f  cap  1
+    u  cap  1
+    v  link  0.5
+    w  c2  1
+RHS
+    RHS  cap  10
+    RHS  link  -1
+BOUNDS
+ PL BND  y
+ LO BND  z  1
+ UP BND  z  5
+ BV BND  b
+ FR BND  f
+ MI BND  u
+ UP BND  u  8
+ LO BND  v  2.5
+ FX BND  w  3
+ENDATA
+`
+	var buf bytes.Buffer
+	if err := writerTestModel().WriteMPS(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("WriteMPS() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// written LP text parses back into the same model, with unnamed constraints given their default names
+func TestWriteLPRoundTrip(t *testing.T) {
+	m := writerTestModel()
+	var buf bytes.Buffer
+	if err := m.WriteLP(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := lpformat.Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() error = %v, LP:\n%s", err, buf.String())
+	}
+	for r := range m.Constraints {
+		m.SetRowName(r, m.ConstraintName(r))
+	}
+	if !reflect.DeepEqual(got.Vars, m.Vars) {
+		t.Errorf("vars = %v, want %v", got.Vars, m.Vars)
+	}
+	if !reflect.DeepEqual(got.Objective, m.Objective) || got.Maximize != m.Maximize {
+		t.Errorf("objective = %v (maximize %v), want %v (maximize %v)", got.Objective, got.Maximize, m.Objective, m.Maximize)
+	}
+	if !reflect.DeepEqual(got.Constraints, m.Constraints) {
+		t.Errorf("constraints = %v, want %v", got.Constraints, m.Constraints)
+	}
+}
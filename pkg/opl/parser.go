@@ -0,0 +1,228 @@
+package opl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parsed output of an oplrun command
+//
+// Recognized lines are:
+//
+//	OBJECTIVE: value          (printed by oplrun)
+//	<<< no solution           (printed by oplrun)
+//	STATUS: code              (CPLEX status, printed by model)
+//	ITERATIONS: count         (printed by model)
+//	NODES: count              (printed by model)
+//	BEST_BOUND: value         (printed by model)
+//	VALUE name value          (printed by model)
+//	name = [ v1 v2 ... ]      (printed by model, may span lines and be nested)
+type Output struct {
+	HasObjective bool
+	Objective    float64
+	NoSolution   bool
+
+	HasStatus bool
+	Status    int // CPLEX status code
+
+	Iterations   int64
+	Nodes        int64
+	HasBestBound bool
+	BestBound    float64
+
+	Values map[string]float64   // scalar values
+	Arrays map[string][]float64 // array values, flattened in row major order
+}
+
+// error in OPL output, with line number
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("OPL output line %d: %s", e.Line, e.Msg)
+}
+
+// parse output of oplrun
+func Parse(r io.Reader) (*Output, error) {
+	out := &Output{
+		Values: make(map[string]float64),
+		Arrays: make(map[string][]float64),
+	}
+
+	// array being read, possibly spanning lines
+	var arrayName string
+	var arrayValues []float64
+	var arrayDepth, arrayLine int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		if arrayName != "" {
+			var err error
+			if arrayValues, arrayDepth, err = scanArray(line, arrayValues, arrayDepth); err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("array %s: %v", arrayName, err)}
+			}
+			if arrayDepth == 0 {
+				out.Arrays[arrayName] = arrayValues
+				arrayName = ""
+			}
+			continue
+		}
+
+		switch {
+		case line == "<<< no solution":
+			out.NoSolution = true
+		case strings.HasPrefix(line, "OBJECTIVE:"):
+			v, err := parseFloat(strings.TrimPrefix(line, "OBJECTIVE:"))
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("objective: %v", err)}
+			}
+			out.Objective = v
+			out.HasObjective = true
+		case strings.HasPrefix(line, "STATUS:"):
+			v, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "STATUS:")))
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("status: %v", err)}
+			}
+			out.Status = v
+			out.HasStatus = true
+		case strings.HasPrefix(line, "ITERATIONS:"):
+			v, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "ITERATIONS:")), 10, 64)
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("iterations: %v", err)}
+			}
+			out.Iterations = v
+		case strings.HasPrefix(line, "NODES:"):
+			v, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "NODES:")), 10, 64)
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("nodes: %v", err)}
+			}
+			out.Nodes = v
+		case strings.HasPrefix(line, "BEST_BOUND:"):
+			v, err := parseFloat(strings.TrimPrefix(line, "BEST_BOUND:"))
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("best bound: %v", err)}
+			}
+			out.BestBound = v
+			out.HasBestBound = true
+		case strings.HasPrefix(line, "VALUE "):
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				return nil, &ParseError{Line: lineNum, Msg: "value: expecting name and value"}
+			}
+			v, err := parseFloat(fields[2])
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("value of %s: %v", fields[1], err)}
+			}
+			out.Values[fields[1]] = v
+		default:
+			// array: name = [ ...
+			name, rest, ok := strings.Cut(line, "=")
+			name = strings.TrimSpace(name)
+			rest = strings.TrimSpace(rest)
+			if !ok || !isIdentifier(name) || !strings.HasPrefix(rest, "[") {
+				// other output, e.g. solver log
+				continue
+			}
+			var err error
+			if arrayValues, arrayDepth, err = scanArray(rest, nil, 0); err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("array %s: %v", name, err)}
+			}
+			if arrayDepth == 0 {
+				out.Arrays[name] = arrayValues
+			} else {
+				arrayName = name
+				arrayLine = lineNum
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if arrayName != "" {
+		return nil, &ParseError{Line: arrayLine, Msg: fmt.Sprintf("array %s: missing closing bracket", arrayName)}
+	}
+	return out, nil
+}
+
+// scan array text, appending values and tracking bracket depth
+func scanArray(text string, values []float64, depth int) ([]float64, int, error) {
+	token := strings.Builder{}
+	flush := func() error {
+		if token.Len() == 0 {
+			return nil
+		}
+		v, err := parseFloat(token.String())
+		token.Reset()
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+		return nil
+	}
+	for _, c := range text {
+		switch {
+		case c == '[':
+			if err := flush(); err != nil {
+				return nil, 0, err
+			}
+			depth++
+		case c == ']':
+			if err := flush(); err != nil {
+				return nil, 0, err
+			}
+			depth--
+			if depth < 0 {
+				return nil, 0, fmt.Errorf("unbalanced closing bracket")
+			}
+		case c == ' ' || c == '\t' || c == ',' || c == ';':
+			if err := flush(); err != nil {
+				return nil, 0, err
+			}
+		default:
+			if depth == 0 {
+				return nil, 0, fmt.Errorf("unexpected %q after array", c)
+			}
+			token.WriteRune(c)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, 0, err
+	}
+	return values, depth, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("missing number")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %q", s)
+	}
+	return v, nil
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(i > 0 && isDigit) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package opl
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *Output
+	}{
+		{
+			name: "solution",
+			input: `
+Version identifier: 22.1.1.0
+OBJECTIVE: 12.5
+BEST_BOUND: 12
+VALUE x_0_1 3
+VALUE y 1e-9
+STATUS: 101
+ITERATIONS: 42
+NODES: 7
+`,
+			want: &Output{
+				HasObjective: true, Objective: 12.5,
+				HasStatus: true, Status: StatusMIPOptimal,
+				Iterations: 42, Nodes: 7,
+				HasBestBound: true, BestBound: 12,
+				Values: map[string]float64{"x_0_1": 3, "y": 1e-9},
+				Arrays: map[string][]float64{},
+			},
+		},
+		{
+			name:  "no solution",
+			input: "<<< no solution\nSTATUS: 103\nITERATIONS: 0\nNODES: 0\n",
+			want: &Output{
+				NoSolution: true,
+				HasStatus:  true, Status: StatusMIPInfeasible,
+				Values: map[string]float64{},
+				Arrays: map[string][]float64{},
+			},
+		},
+		{
+			name:  "arrays",
+			input: "numReplicas = [[1 0]\n  [0 2]];\nrates = [0.5, 1.5];\nnote = this is not an array\n",
+			want: &Output{
+				Values: map[string]float64{},
+				Arrays: map[string][]float64{"numReplicas": {1, 0, 0, 2}, "rates": {0.5, 1.5}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+		msg   string
+	}{
+		{name: "objective missing", input: "OBJECTIVE:\n", line: 1, msg: "objective: missing number"},
+		{name: "objective malformed", input: "log\nOBJECTIVE: 1.2.3\n", line: 2, msg: `objective: bad number "1.2.3"`},
+		{name: "status malformed", input: "OBJECTIVE: 1\nSTATUS: optimal\n", line: 2, msg: "status:"},
+		{name: "status float", input: "STATUS: 101.0\n", line: 1, msg: "status:"},
+		{name: "iterations malformed", input: "ITERATIONS: many\n", line: 1, msg: "iterations:"},
+		{name: "nodes malformed", input: "NODES: -\n", line: 1, msg: "nodes:"},
+		{name: "best bound malformed", input: "BEST_BOUND: inf-\n", line: 1, msg: "best bound: bad number"},
+		{name: "value missing", input: "VALUE x\n", line: 1, msg: "value: expecting name and value"},
+		{name: "value extra field", input: "VALUE x 1 2\n", line: 1, msg: "value: expecting name and value"},
+		{name: "value malformed", input: "\n\nVALUE x one\n", line: 3, msg: `value of x: bad number "one"`},
+		{name: "array unclosed", input: "a = [1 2\n3\n\nOBJECTIVE: 1\n", line: 4, msg: `array a: bad number "OBJECTIVE:"`},
+		{name: "array unclosed at end", input: "log\na = [[1 2]\n[3 4]\n", line: 2, msg: "array a: missing closing bracket"},
+		{name: "array extra bracket", input: "a = [1 2]]\n", line: 1, msg: "array a: unbalanced closing bracket"},
+		{name: "array extra bracket on later line", input: "a = [[1]\n[2]]]\n", line: 2, msg: "array a: unbalanced closing bracket"},
+		{name: "array trailing text", input: "a = [1] x\n", line: 1, msg: `array a: unexpected 'x' after array`},
+		{name: "array malformed", input: "a = [1 two]\n", line: 1, msg: `array a: bad number "two"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse() error = %v, want *ParseError", err)
+			}
+			if parseErr.Line != tt.line {
+				t.Errorf("line = %d, want %d", parseErr.Line, tt.line)
+			}
+			if !strings.HasPrefix(parseErr.Msg, tt.msg) {
+				t.Errorf("message = %q, want prefix %q", parseErr.Msg, tt.msg)
+			}
+		})
+	}
+}
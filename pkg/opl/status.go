@@ -0,0 +1,70 @@
+package opl
+
+import "fmt"
+
+// CPLEX solution status codes, as returned by cplex.getCplexStatus()
+const (
+	StatusOptimal          = 1   // optimal solution
+	StatusUnbounded        = 2   // problem is unbounded
+	StatusInfeasible       = 3   // problem is infeasible
+	StatusInfOrUnbd        = 4   // problem is infeasible or unbounded
+	StatusOptimalInfeas    = 5   // optimal with unscaled infeasibilities
+	StatusNumBest          = 6   // solution available, numerical difficulties
+	StatusAbortItLim       = 10  // iteration limit exceeded
+	StatusAbortTimeLim     = 11  // time limit exceeded
+	StatusAbortUser        = 13  // aborted by user
+	StatusMIPOptimal       = 101 // integer optimal solution
+	StatusMIPOptimalTol    = 102 // integer optimal solution, within tolerance
+	StatusMIPInfeasible    = 103 // integer infeasible
+	StatusMIPSolLim        = 104 // solution limit exceeded
+	StatusMIPNodeLimFeas   = 105 // node limit exceeded, integer solution exists
+	StatusMIPNodeLimInfeas = 106 // node limit exceeded, no integer solution
+	StatusMIPTimeLimFeas   = 107 // time limit exceeded, integer solution exists
+	StatusMIPTimeLimInfeas = 108 // time limit exceeded, no integer solution
+	StatusMIPFailFeas      = 109 // error, integer solution exists
+	StatusMIPFailInfeas    = 110 // error, no integer solution
+	StatusMIPMemLimFeas    = 111 // memory limit exceeded, integer solution exists
+	StatusMIPMemLimInfeas  = 112 // memory limit exceeded, no integer solution
+	StatusMIPAbortFeas     = 113 // aborted, integer solution exists
+	StatusMIPAbortInfeas   = 114 // aborted, no integer solution
+	StatusMIPOptimalInfeas = 115 // integer optimal with unscaled infeasibilities
+	StatusMIPUnbounded     = 118 // problem is unbounded
+	StatusMIPInfOrUnbd     = 119 // problem is infeasible or unbounded
+)
+
+var statusNames = map[int]string{
+	StatusOptimal:          "optimal",
+	StatusUnbounded:        "unbounded",
+	StatusInfeasible:       "infeasible",
+	StatusInfOrUnbd:        "infeasible or unbounded",
+	StatusOptimalInfeas:    "optimal with unscaled infeasibilities",
+	StatusNumBest:          "numerical difficulties",
+	StatusAbortItLim:       "iteration limit exceeded",
+	StatusAbortTimeLim:     "time limit exceeded",
+	StatusAbortUser:        "aborted by user",
+	StatusMIPOptimal:       "integer optimal",
+	StatusMIPOptimalTol:    "integer optimal, tolerance",
+	StatusMIPInfeasible:    "integer infeasible",
+	StatusMIPSolLim:        "solution limit exceeded",
+	StatusMIPNodeLimFeas:   "node limit exceeded, integer solution exists",
+	StatusMIPNodeLimInfeas: "node limit exceeded, no integer solution",
+	StatusMIPTimeLimFeas:   "time limit exceeded, integer solution exists",
+	StatusMIPTimeLimInfeas: "time limit exceeded, no integer solution",
+	StatusMIPFailFeas:      "error, integer solution exists",
+	StatusMIPFailInfeas:    "error, no integer solution",
+	StatusMIPMemLimFeas:    "memory limit exceeded, integer solution exists",
+	StatusMIPMemLimInfeas:  "memory limit exceeded, no integer solution",
+	StatusMIPAbortFeas:     "aborted, integer solution exists",
+	StatusMIPAbortInfeas:   "aborted, no integer solution",
+	StatusMIPOptimalInfeas: "integer optimal with unscaled infeasibilities",
+	StatusMIPUnbounded:     "unbounded",
+	StatusMIPInfOrUnbd:     "infeasible or unbounded",
+}
+
+// description of CPLEX status code
+func StatusName(status int) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown status %d", status)
+}